@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // Account represents individual accounts in a Xero organisation
@@ -21,25 +20,25 @@ type Account struct {
 	Name string `json:"Name,omitempty" xml:"Name,omitempty"`
 
 	// See Account Types
-	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
+	Type AccountType `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// For bank accounts only (Account Type BANK)
 	BankAccountNumber string `json:"BankAccountNumber,omitempty" xml:"BankAccountNumber,omitempty"`
 
 	// Accounts with a status of ACTIVE can be updated to ARCHIVED. See Account Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status AccountStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Description of the Account. Valid for all types of accounts except bank accounts (max length = 4000)
 	Description string `json:"Description,omitempty" xml:"Description,omitempty"`
 
 	// For bank accounts only. See Bank Account types
-	BankAccountType string `json:"BankAccountType,omitempty" xml:"BankAccountType,omitempty"`
+	BankAccountType BankAccountType `json:"BankAccountType,omitempty" xml:"BankAccountType,omitempty"`
 
 	// For bank accounts only
 	CurrencyCode string `json:"CurrencyCode,omitempty" xml:"CurrencyCode,omitempty"`
 
 	// See Tax Types
-	TaxType string `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
+	TaxType TaxType `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
 
 	// Boolean – describes whether account can have payments applied to it
 	EnablePaymentsToAccount bool `json:"EnablePaymentsToAccount,omitempty" xml:"EnablePaymentsToAccount,omitempty"`
@@ -51,7 +50,7 @@ type Account struct {
 	AccountID string `json:"AccountID,omitempty" xml:"AccountID,omitempty"`
 
 	// See Account Class Types
-	Class string `json:"Class,omitempty" xml:"-"`
+	Class AccountClass `json:"Class,omitempty" xml:"-"`
 
 	// If this is a system account then this element is returned. See System Account types. Note that non-system accounts may have this element set as either “” or null.
 	SystemAccount string `json:"SystemAccount,omitempty" xml:"-"`
@@ -66,7 +65,7 @@ type Account struct {
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"-"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 }
 
 // Accounts contains a collection of Accounts
@@ -74,20 +73,6 @@ type Accounts struct {
 	Accounts []Account `json:"Accounts,omitempty" xml:"Account,omitempty"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (a *Accounts) convertDates() error {
-	var err error
-	for n := len(a.Accounts) - 1; n >= 0; n-- {
-		a.Accounts[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(a.Accounts[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalAccount(accountResponseBytes []byte) (*Accounts, error) {
 	var accountResponse *Accounts
 	err := json.Unmarshal(accountResponseBytes, &accountResponse)
@@ -95,11 +80,6 @@ func unmarshalAccount(accountResponseBytes []byte) (*Accounts, error) {
 		return nil, err
 	}
 
-	err = accountResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return accountResponse, err
 }
 
@@ -2,7 +2,7 @@ package accounting
 
 // Address is an address for a contact
 type Address struct {
-	AddressType string `json:"AddressType,omitempty" xml:"AddressType,omitempty"`
+	AddressType AddressType `json:"AddressType,omitempty" xml:"AddressType,omitempty"`
 
 	// max length = 500
 	AddressLine1 string `json:"AddressLine1,omitempty" xml:"AddressLine1,omitempty"`
@@ -1,15 +1,21 @@
 package accounting
 
-import "github.com/shopspring/decimal"
+import (
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+)
 
 // Allocation allocated an overpayment or Prepayment to an Invoice
 type Allocation struct {
 
+	// Xero generated unique identifier for the allocation (read-only)
+	AllocationID string `json:"AllocationID,omitempty" xml:"-"`
+
 	// the amount being applied to the invoice
 	AppliedAmount decimal.Decimal `json:"AppliedAmount,omitempty" xml:"AppliedAmount,omitempty"`
 
 	// the date the prepayment is applied YYYY-MM-DD (read-only). This will be the latter of the invoice date and the prepayment date.
-	Date string `json:"Date,omitempty" xml:"-"`
+	Date xerogolang.Date `json:"Date,omitzero" xml:"-"`
 
 	//The Invoice that the allocation will be made to
 	Invoice InvoiceID `json:"Invoice,omitempty" xml:"Invoice>InvoiceID,omitempty"`
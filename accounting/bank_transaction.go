@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -16,7 +15,7 @@ import (
 type BankTransaction struct {
 
 	// See Bank Transaction Types
-	Type string `json:"Type" xml:"Type"`
+	Type BankTransactionType `json:"Type" xml:"Type"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -28,7 +27,7 @@ type BankTransaction struct {
 	IsReconciled bool `json:"IsReconciled,omitempty" xml:"IsReconciled,omitempty"`
 
 	// Date of transaction – YYYY-MM-DD
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// Reference for the transaction. Only supported for SPEND and RECEIVE transactions.
 	Reference string `json:"Reference,omitempty" xml:"Reference,omitempty"`
@@ -43,10 +42,10 @@ type BankTransaction struct {
 	URL string `json:"Url,omitempty" xml:"Url,omitempty"`
 
 	// See Bank Transaction Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status BankTransactionStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Line amounts are exclusive of tax by default if you don’t specify this element. See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// Total of bank transaction excluding taxes
 	SubTotal decimal.Decimal `json:"SubTotal,omitempty" xml:"SubTotal,omitempty"`
@@ -70,7 +69,7 @@ type BankTransaction struct {
 	OverpaymentID string `json:"OverpaymentID,omitempty" xml:"-"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// Boolean to indicate if a bank transaction has an attachment
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"-"`
@@ -81,20 +80,6 @@ type BankTransactions struct {
 	BankTransactions []BankTransaction `json:"BankTransactions" xml:"BankTransaction"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (b *BankTransactions) convertDates() error {
-	var err error
-	for n := len(b.BankTransactions) - 1; n >= 0; n-- {
-		b.BankTransactions[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(b.BankTransactions[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalBankTransaction(bankTransactionResponseBytes []byte) (*BankTransactions, error) {
 	var bankTransactionResponse *BankTransactions
 	err := json.Unmarshal(bankTransactionResponseBytes, &bankTransactionResponse)
@@ -102,11 +87,6 @@ func unmarshalBankTransaction(bankTransactionResponseBytes []byte) (*BankTransac
 		return nil, err
 	}
 
-	err = bankTransactionResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return bankTransactionResponse, err
 }
 
@@ -179,6 +159,39 @@ func FindBankTransactions(ctx context.Context, provider xerogolang.IProvider, se
 	return FindBankTransactionsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// BankTransactionsOptions are the typed filters accepted by FindBankTransactionsWithOptions
+type BankTransactionsOptions struct {
+	ListOptions
+
+	// Decimal places for unit amounts - 2 or 4
+	UnitDP int
+}
+
+func (o *BankTransactionsOptions) querystringParameters() (map[string]string, error) {
+	if err := validateUnitDP(o.UnitDP); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addUnitDP(params, o.UnitDP)
+
+	return params, nil
+}
+
+// FindBankTransactionsWithOptions will get all BankTransactions matching the given options
+func FindBankTransactionsWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *BankTransactionsOptions) (*BankTransactions, error) {
+	if options == nil {
+		options = &BankTransactionsOptions{}
+	}
+
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindBankTransactionsModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+}
+
 // FindBankTransaction will get a single BankTransaction - BankTransactionID can be a GUID for an BankTransaction or an BankTransaction number
 func FindBankTransaction(ctx context.Context, provider xerogolang.IProvider, session goth.Session, bankTransactionID string) (*BankTransactions, error) {
 	additionalHeaders := map[string]string{
@@ -211,7 +224,7 @@ func GenerateExampleBankTransaction() *BankTransactions {
 		Contact: Contact{
 			Name: "George Costanza",
 		},
-		Date:        helpers.TodayRFC3339(),
+		Date:        xerogolang.Today(),
 		LineItems:   []LineItem{},
 		BankAccount: bankAccount,
 	}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -19,7 +18,7 @@ type BankTransfer struct {
 	Amount decimal.Decimal `json:"Amount" xml:"Amount"`
 
 	// The date of the Transfer YYYY-MM-DD
-	Date string `json:"Date,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"Date,omitzero" xml:"Date,omitempty"`
 
 	// The identifier of the Bank Transfer
 	BankTransferID string `json:"BankTransferID,omitempty" xml:"BankTransferID,omitempty"`
@@ -37,7 +36,7 @@ type BankTransfer struct {
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"HasAttachments,omitempty"`
 
 	// UTC timestamp of creation date of bank transfer
-	CreatedDateUTC string `json:"CreatedDateUTC,omitempty" xml:"CreatedDateUTC,omitempty"`
+	CreatedDateUTC xerogolang.DateTime `json:"CreatedDateUTC,omitzero" xml:"CreatedDateUTC,omitempty"`
 
 	// The source BankAccount
 	FromBankAccount BankAccount `json:"FromBankAccount,omitempty" xml:"FromBankAccount,omitempty"`
@@ -51,24 +50,6 @@ type BankTransfers struct {
 	BankTransfers []BankTransfer `json:"BankTransfers" xml:"BankTransfer"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (b *BankTransfers) convertDates() error {
-	var err error
-	for n := len(b.BankTransfers) - 1; n >= 0; n-- {
-		b.BankTransfers[n].Date, err = helpers.DotNetJSONTimeToRFC3339(b.BankTransfers[n].Date, false)
-		if err != nil {
-			return err
-		}
-		b.BankTransfers[n].CreatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(b.BankTransfers[n].CreatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalBankTransfer(bankTransferResponseBytes []byte) (*BankTransfers, error) {
 	var bankTransferResponse *BankTransfers
 	err := json.Unmarshal(bankTransferResponseBytes, &bankTransferResponse)
@@ -76,11 +57,6 @@ func unmarshalBankTransfer(bankTransferResponseBytes []byte) (*BankTransfers, er
 		return nil, err
 	}
 
-	err = bankTransferResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return bankTransferResponse, err
 }
 
@@ -6,7 +6,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // BrandingTheme applies structure and visuals to an invoice when printed or sent
@@ -22,7 +21,7 @@ type BrandingTheme struct {
 	SortOrder float64 `json:"SortOrder,omitempty" xml:"SortOrder,omitempty"`
 
 	// UTC timestamp of creation date of branding theme
-	CreatedDateUTC string `json:"CreatedDateUTC,omitempty" xml:"CreatedDateUTC,omitempty"`
+	CreatedDateUTC xerogolang.DateTime `json:"CreatedDateUTC,omitzero" xml:"CreatedDateUTC,omitempty"`
 }
 
 // BrandingThemes contains a collection of BrandingThemes
@@ -30,20 +29,6 @@ type BrandingThemes struct {
 	BrandingThemes []BrandingTheme `json:"BrandingThemes" xml:"BrandingTheme"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (b *BrandingThemes) convertDates() error {
-	var err error
-	for n := len(b.BrandingThemes) - 1; n >= 0; n-- {
-		b.BrandingThemes[n].CreatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(b.BrandingThemes[n].CreatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalBrandingTheme(brandingThemeResponseBytes []byte) (*BrandingThemes, error) {
 	var brandingThemeResponse *BrandingThemes
 	err := json.Unmarshal(brandingThemeResponseBytes, &brandingThemeResponse)
@@ -51,11 +36,6 @@ func unmarshalBrandingTheme(brandingThemeResponseBytes []byte) (*BrandingThemes,
 		return nil, err
 	}
 
-	err = brandingThemeResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return brandingThemeResponse, err
 }
 
@@ -0,0 +1,697 @@
+package accounting
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// OnUnknownCode is called when a code read from Xero is not in the code lists of this package. Unknown codes are
+// kept as they are, since newer versions of the API add codes - set it to log them
+var OnUnknownCode func(codeList string, code string)
+
+// unmarshalCode reads a code from JSON. A code must be a string, null is read as an empty code
+func unmarshalCode(data []byte, codeList string, known func(string) bool) (string, error) {
+	var code string
+	if err := json.Unmarshal(data, &code); err != nil {
+		return "", fmt.Errorf("%s must be a string, not %s", codeList, string(data))
+	}
+	if code != "" && !known(code) && OnUnknownCode != nil {
+		OnUnknownCode(codeList, code)
+	}
+	return code, nil
+}
+
+func knownCode(code string, codes ...string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// InvoiceType is the type of an invoice
+type InvoiceType string
+
+// Invoice types
+const (
+	// InvoiceTypeAccRec is a sales invoice
+	InvoiceTypeAccRec InvoiceType = "ACCREC"
+
+	// InvoiceTypeAccPay is a bill
+	InvoiceTypeAccPay InvoiceType = "ACCPAY"
+)
+
+func (t InvoiceType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the invoice types above
+func (t InvoiceType) IsKnown() bool {
+	return knownCode(string(t), "ACCREC", "ACCPAY")
+}
+
+// IsReceivable reports whether the invoice is a sales invoice
+func (t InvoiceType) IsReceivable() bool {
+	return t == InvoiceTypeAccRec
+}
+
+// IsPayable reports whether the invoice is a bill
+func (t InvoiceType) IsPayable() bool {
+	return t == InvoiceTypeAccPay
+}
+
+// CreditNoteType returns the type of the credit notes that credit an invoice of type t
+func (t InvoiceType) CreditNoteType() CreditNoteType {
+	switch t {
+	case InvoiceTypeAccRec:
+		return CreditNoteTypeAccRecCredit
+	case InvoiceTypeAccPay:
+		return CreditNoteTypeAccPayCredit
+	}
+	return ""
+}
+
+// UnmarshalJSON keeps unknown invoice types, see OnUnknownCode
+func (t *InvoiceType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "InvoiceType", func(c string) bool { return InvoiceType(c).IsKnown() })
+	*t = InvoiceType(code)
+	return err
+}
+
+// CreditNoteType is the type of a credit note
+type CreditNoteType string
+
+// Credit note types
+const (
+	// CreditNoteTypeAccRecCredit is a credit note to a customer
+	CreditNoteTypeAccRecCredit CreditNoteType = "ACCRECCREDIT"
+
+	// CreditNoteTypeAccPayCredit is a credit note from a supplier
+	CreditNoteTypeAccPayCredit CreditNoteType = "ACCPAYCREDIT"
+)
+
+func (t CreditNoteType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the credit note types above
+func (t CreditNoteType) IsKnown() bool {
+	return knownCode(string(t), "ACCRECCREDIT", "ACCPAYCREDIT")
+}
+
+// IsReceivable reports whether the credit note is to a customer
+func (t CreditNoteType) IsReceivable() bool {
+	return t == CreditNoteTypeAccRecCredit
+}
+
+// IsPayable reports whether the credit note is from a supplier
+func (t CreditNoteType) IsPayable() bool {
+	return t == CreditNoteTypeAccPayCredit
+}
+
+// InvoiceType returns the type of the invoices a credit note of type t is allocated to
+func (t CreditNoteType) InvoiceType() InvoiceType {
+	switch t {
+	case CreditNoteTypeAccRecCredit:
+		return InvoiceTypeAccRec
+	case CreditNoteTypeAccPayCredit:
+		return InvoiceTypeAccPay
+	}
+	return ""
+}
+
+// UnmarshalJSON keeps unknown credit note types, see OnUnknownCode
+func (t *CreditNoteType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "CreditNoteType", func(c string) bool { return CreditNoteType(c).IsKnown() })
+	*t = CreditNoteType(code)
+	return err
+}
+
+// BankTransactionType is the type of a bank transaction, overpayment or prepayment
+type BankTransactionType string
+
+// Bank transaction types
+const (
+	BankTransactionTypeReceive            BankTransactionType = "RECEIVE"
+	BankTransactionTypeReceiveOverpayment BankTransactionType = "RECEIVE-OVERPAYMENT"
+	BankTransactionTypeReceivePrepayment  BankTransactionType = "RECEIVE-PREPAYMENT"
+	BankTransactionTypeReceiveTransfer    BankTransactionType = "RECEIVE-TRANSFER"
+	BankTransactionTypeSpend              BankTransactionType = "SPEND"
+	BankTransactionTypeSpendOverpayment   BankTransactionType = "SPEND-OVERPAYMENT"
+	BankTransactionTypeSpendPrepayment    BankTransactionType = "SPEND-PREPAYMENT"
+	BankTransactionTypeSpendTransfer      BankTransactionType = "SPEND-TRANSFER"
+)
+
+func (t BankTransactionType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the bank transaction types above
+func (t BankTransactionType) IsKnown() bool {
+	return knownCode(string(t), "RECEIVE", "RECEIVE-OVERPAYMENT", "RECEIVE-PREPAYMENT", "RECEIVE-TRANSFER",
+		"SPEND", "SPEND-OVERPAYMENT", "SPEND-PREPAYMENT", "SPEND-TRANSFER")
+}
+
+// IsReceive reports whether money comes into the bank account
+func (t BankTransactionType) IsReceive() bool {
+	return strings.HasPrefix(string(t), "RECEIVE")
+}
+
+// IsSpend reports whether money leaves the bank account
+func (t BankTransactionType) IsSpend() bool {
+	return strings.HasPrefix(string(t), "SPEND")
+}
+
+// IsOverpayment reports whether the transaction is an overpayment
+func (t BankTransactionType) IsOverpayment() bool {
+	return strings.HasSuffix(string(t), "-OVERPAYMENT")
+}
+
+// IsPrepayment reports whether the transaction is a prepayment
+func (t BankTransactionType) IsPrepayment() bool {
+	return strings.HasSuffix(string(t), "-PREPAYMENT")
+}
+
+// IsTransfer reports whether the transaction is one side of a bank transfer
+func (t BankTransactionType) IsTransfer() bool {
+	return strings.HasSuffix(string(t), "-TRANSFER")
+}
+
+// UnmarshalJSON keeps unknown bank transaction types, see OnUnknownCode
+func (t *BankTransactionType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "BankTransactionType", func(c string) bool { return BankTransactionType(c).IsKnown() })
+	*t = BankTransactionType(code)
+	return err
+}
+
+// AccountClass groups account types into the sections of the balance sheet and profit and loss
+type AccountClass string
+
+// Account classes
+const (
+	AccountClassAsset     AccountClass = "ASSET"
+	AccountClassEquity    AccountClass = "EQUITY"
+	AccountClassExpense   AccountClass = "EXPENSE"
+	AccountClassLiability AccountClass = "LIABILITY"
+	AccountClassRevenue   AccountClass = "REVENUE"
+)
+
+func (c AccountClass) String() string {
+	return string(c)
+}
+
+// IsKnown reports whether c is one of the account classes above
+func (c AccountClass) IsKnown() bool {
+	return knownCode(string(c), "ASSET", "EQUITY", "EXPENSE", "LIABILITY", "REVENUE")
+}
+
+// IsProfitAndLoss reports whether accounts of the class are on the profit and loss
+func (c AccountClass) IsProfitAndLoss() bool {
+	return c == AccountClassRevenue || c == AccountClassExpense
+}
+
+// IsBalanceSheet reports whether accounts of the class are on the balance sheet
+func (c AccountClass) IsBalanceSheet() bool {
+	return c == AccountClassAsset || c == AccountClassEquity || c == AccountClassLiability
+}
+
+// UnmarshalJSON keeps unknown account classes, see OnUnknownCode
+func (c *AccountClass) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "AccountClass", func(v string) bool { return AccountClass(v).IsKnown() })
+	*c = AccountClass(code)
+	return err
+}
+
+// AccountType is the type of an account
+type AccountType string
+
+// Account types
+const (
+	AccountTypeBank                    AccountType = "BANK"
+	AccountTypeCurrent                 AccountType = "CURRENT"
+	AccountTypeCurrentLiability        AccountType = "CURRLIAB"
+	AccountTypeDepreciation            AccountType = "DEPRECIATN"
+	AccountTypeDirectCosts             AccountType = "DIRECTCOSTS"
+	AccountTypeEquity                  AccountType = "EQUITY"
+	AccountTypeExpense                 AccountType = "EXPENSE"
+	AccountTypeFixed                   AccountType = "FIXED"
+	AccountTypeInventory               AccountType = "INVENTORY"
+	AccountTypeLiability               AccountType = "LIABILITY"
+	AccountTypeNonCurrent              AccountType = "NONCURRENT"
+	AccountTypeOtherIncome             AccountType = "OTHERINCOME"
+	AccountTypeOverheads               AccountType = "OVERHEADS"
+	AccountTypePrepayment              AccountType = "PREPAYMENT"
+	AccountTypeRevenue                 AccountType = "REVENUE"
+	AccountTypeSales                   AccountType = "SALES"
+	AccountTypeTermLiability           AccountType = "TERMLIAB"
+	AccountTypePAYGLiability           AccountType = "PAYGLIABILITY"
+	AccountTypeSuperannuationExpense   AccountType = "SUPERANNUATIONEXPENSE"
+	AccountTypeSuperannuationLiability AccountType = "SUPERANNUATIONLIABILITY"
+	AccountTypeWagesExpense            AccountType = "WAGESEXPENSE"
+)
+
+// accountTypeClasses maps each account type to its class
+var accountTypeClasses = map[AccountType]AccountClass{
+	AccountTypeBank:                    AccountClassAsset,
+	AccountTypeCurrent:                 AccountClassAsset,
+	AccountTypeFixed:                   AccountClassAsset,
+	AccountTypeInventory:               AccountClassAsset,
+	AccountTypeNonCurrent:              AccountClassAsset,
+	AccountTypePrepayment:              AccountClassAsset,
+	AccountTypeEquity:                  AccountClassEquity,
+	AccountTypeCurrentLiability:        AccountClassLiability,
+	AccountTypeLiability:               AccountClassLiability,
+	AccountTypeTermLiability:           AccountClassLiability,
+	AccountTypePAYGLiability:           AccountClassLiability,
+	AccountTypeSuperannuationLiability: AccountClassLiability,
+	AccountTypeDepreciation:            AccountClassExpense,
+	AccountTypeDirectCosts:             AccountClassExpense,
+	AccountTypeExpense:                 AccountClassExpense,
+	AccountTypeOverheads:               AccountClassExpense,
+	AccountTypeSuperannuationExpense:   AccountClassExpense,
+	AccountTypeWagesExpense:            AccountClassExpense,
+	AccountTypeOtherIncome:             AccountClassRevenue,
+	AccountTypeRevenue:                 AccountClassRevenue,
+	AccountTypeSales:                   AccountClassRevenue,
+}
+
+func (t AccountType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the account types above
+func (t AccountType) IsKnown() bool {
+	_, ok := accountTypeClasses[t]
+	return ok
+}
+
+// Class returns the class of accounts of type t, or an empty class for unknown types
+func (t AccountType) Class() AccountClass {
+	return accountTypeClasses[t]
+}
+
+// UnmarshalJSON keeps unknown account types, see OnUnknownCode
+func (t *AccountType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "AccountType", func(c string) bool { return AccountType(c).IsKnown() })
+	*t = AccountType(code)
+	return err
+}
+
+// AccountStatus is the status of an account
+type AccountStatus string
+
+// Account statuses
+const (
+	AccountStatusActive   AccountStatus = "ACTIVE"
+	AccountStatusArchived AccountStatus = "ARCHIVED"
+)
+
+func (s AccountStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the account statuses above
+func (s AccountStatus) IsKnown() bool {
+	return knownCode(string(s), "ACTIVE", "ARCHIVED")
+}
+
+// UnmarshalJSON keeps unknown account statuses, see OnUnknownCode
+func (s *AccountStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "AccountStatus", func(c string) bool { return AccountStatus(c).IsKnown() })
+	*s = AccountStatus(code)
+	return err
+}
+
+// BankAccountType is the type of a bank account
+type BankAccountType string
+
+// Bank account types
+const (
+	BankAccountTypeBank       BankAccountType = "BANK"
+	BankAccountTypeCreditCard BankAccountType = "CREDITCARD"
+	BankAccountTypePaypal     BankAccountType = "PAYPAL"
+)
+
+func (t BankAccountType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the bank account types above
+func (t BankAccountType) IsKnown() bool {
+	return knownCode(string(t), "BANK", "CREDITCARD", "PAYPAL")
+}
+
+// UnmarshalJSON keeps unknown bank account types, see OnUnknownCode
+func (t *BankAccountType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "BankAccountType", func(c string) bool { return BankAccountType(c).IsKnown() })
+	*t = BankAccountType(code)
+	return err
+}
+
+// LineAmountType says whether the line amounts of a document include tax
+type LineAmountType string
+
+// Line amount types
+const (
+	LineAmountTypeExclusive LineAmountType = "Exclusive"
+	LineAmountTypeInclusive LineAmountType = "Inclusive"
+	LineAmountTypeNoTax     LineAmountType = "NoTax"
+)
+
+func (t LineAmountType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the line amount types above
+func (t LineAmountType) IsKnown() bool {
+	return knownCode(string(t), "Exclusive", "Inclusive", "NoTax")
+}
+
+// HasTax reports whether tax is charged on the lines
+func (t LineAmountType) HasTax() bool {
+	return t != LineAmountTypeNoTax
+}
+
+// UnmarshalJSON keeps unknown line amount types, see OnUnknownCode
+func (t *LineAmountType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "LineAmountType", func(c string) bool { return LineAmountType(c).IsKnown() })
+	*t = LineAmountType(code)
+	return err
+}
+
+// TaxType is the code of a tax rate. The tax types differ by region and organisations add their
+// own, which Xero numbers TAX001, TAX002 and so on
+type TaxType string
+
+// Tax types found in most regions
+const (
+	TaxTypeNone         TaxType = "NONE"
+	TaxTypeInput        TaxType = "INPUT"
+	TaxTypeOutput       TaxType = "OUTPUT"
+	TaxTypeExemptInput  TaxType = "EXEMPTINPUT"
+	TaxTypeExemptOutput TaxType = "EXEMPTOUTPUT"
+	TaxTypeGSTOnImports TaxType = "GSTONIMPORTS"
+	TaxTypeInput2       TaxType = "INPUT2"
+	TaxTypeOutput2      TaxType = "OUTPUT2"
+	TaxTypeZeroRated    TaxType = "ZERORATED"
+)
+
+func (t TaxType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the tax types above or a tax type of the organisation
+func (t TaxType) IsKnown() bool {
+	return t.IsCustom() || knownCode(string(t), "NONE", "INPUT", "OUTPUT", "EXEMPTINPUT", "EXEMPTOUTPUT",
+		"GSTONIMPORTS", "INPUT2", "OUTPUT2", "ZERORATED")
+}
+
+// IsCustom reports whether t is a tax rate the organisation added
+func (t TaxType) IsCustom() bool {
+	return strings.HasPrefix(string(t), "TAX")
+}
+
+// UnmarshalJSON keeps unknown tax types, see OnUnknownCode
+func (t *TaxType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "TaxType", func(c string) bool { return TaxType(c).IsKnown() })
+	*t = TaxType(code)
+	return err
+}
+
+// TaxRateStatus is the status of a tax rate
+type TaxRateStatus string
+
+// Tax rate statuses
+const (
+	TaxRateStatusActive   TaxRateStatus = "ACTIVE"
+	TaxRateStatusDeleted  TaxRateStatus = "DELETED"
+	TaxRateStatusArchived TaxRateStatus = "ARCHIVED"
+	TaxRateStatusPending  TaxRateStatus = "PENDING"
+)
+
+func (s TaxRateStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the tax rate statuses above
+func (s TaxRateStatus) IsKnown() bool {
+	return knownCode(string(s), "ACTIVE", "DELETED", "ARCHIVED", "PENDING")
+}
+
+// UnmarshalJSON keeps unknown tax rate statuses, see OnUnknownCode
+func (s *TaxRateStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "TaxRateStatus", func(c string) bool { return TaxRateStatus(c).IsKnown() })
+	*s = TaxRateStatus(code)
+	return err
+}
+
+// ContactStatus is the status of a contact
+type ContactStatus string
+
+// Contact statuses
+const (
+	ContactStatusActive      ContactStatus = "ACTIVE"
+	ContactStatusArchived    ContactStatus = "ARCHIVED"
+	ContactStatusGDPRRequest ContactStatus = "GDPRREQUEST"
+)
+
+func (s ContactStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the contact statuses above
+func (s ContactStatus) IsKnown() bool {
+	return knownCode(string(s), "ACTIVE", "ARCHIVED", "GDPRREQUEST")
+}
+
+// UnmarshalJSON keeps unknown contact statuses, see OnUnknownCode
+func (s *ContactStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "ContactStatus", func(c string) bool { return ContactStatus(c).IsKnown() })
+	*s = ContactStatus(code)
+	return err
+}
+
+// PaymentType is the kind of document a payment pays
+type PaymentType string
+
+// Payment types
+const (
+	PaymentTypeAccRecPayment        PaymentType = "ACCRECPAYMENT"
+	PaymentTypeAccPayPayment        PaymentType = "ACCPAYPAYMENT"
+	PaymentTypeARCreditPayment      PaymentType = "ARCREDITPAYMENT"
+	PaymentTypeAPCreditPayment      PaymentType = "APCREDITPAYMENT"
+	PaymentTypeAROverpaymentPayment PaymentType = "AROVERPAYMENTPAYMENT"
+	PaymentTypeARPrepaymentPayment  PaymentType = "ARPREPAYMENTPAYMENT"
+	PaymentTypeAPPrepaymentPayment  PaymentType = "APPREPAYMENTPAYMENT"
+	PaymentTypeAPOverpaymentPayment PaymentType = "APOVERPAYMENTPAYMENT"
+)
+
+func (t PaymentType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the payment types above
+func (t PaymentType) IsKnown() bool {
+	return knownCode(string(t), "ACCRECPAYMENT", "ACCPAYPAYMENT", "ARCREDITPAYMENT", "APCREDITPAYMENT",
+		"AROVERPAYMENTPAYMENT", "ARPREPAYMENTPAYMENT", "APPREPAYMENTPAYMENT", "APOVERPAYMENTPAYMENT")
+}
+
+// IsReceivable reports whether the payment is on the sales side - a payment received for an invoice or a refund
+// paid on a customer credit note, overpayment or prepayment
+func (t PaymentType) IsReceivable() bool {
+	return t == PaymentTypeAccRecPayment || strings.HasPrefix(string(t), "AR")
+}
+
+// UnmarshalJSON keeps unknown payment types, see OnUnknownCode
+func (t *PaymentType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "PaymentType", func(c string) bool { return PaymentType(c).IsKnown() })
+	*t = PaymentType(code)
+	return err
+}
+
+// PaymentStatus is the status of a payment
+type PaymentStatus string
+
+// Payment statuses
+const (
+	PaymentStatusAuthorised PaymentStatus = "AUTHORISED"
+	PaymentStatusDeleted    PaymentStatus = "DELETED"
+)
+
+func (s PaymentStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the payment statuses above
+func (s PaymentStatus) IsKnown() bool {
+	return knownCode(string(s), "AUTHORISED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown payment statuses, see OnUnknownCode
+func (s *PaymentStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "PaymentStatus", func(c string) bool { return PaymentStatus(c).IsKnown() })
+	*s = PaymentStatus(code)
+	return err
+}
+
+// ManualJournalStatus is the status of a manual journal
+type ManualJournalStatus string
+
+// Manual journal statuses
+const (
+	ManualJournalStatusDraft   ManualJournalStatus = "DRAFT"
+	ManualJournalStatusPosted  ManualJournalStatus = "POSTED"
+	ManualJournalStatusDeleted ManualJournalStatus = "DELETED"
+	ManualJournalStatusVoided  ManualJournalStatus = "VOIDED"
+)
+
+func (s ManualJournalStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the manual journal statuses above
+func (s ManualJournalStatus) IsKnown() bool {
+	return knownCode(string(s), "DRAFT", "POSTED", "DELETED", "VOIDED")
+}
+
+// UnmarshalJSON keeps unknown manual journal statuses, see OnUnknownCode
+func (s *ManualJournalStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "ManualJournalStatus", func(c string) bool { return ManualJournalStatus(c).IsKnown() })
+	*s = ManualJournalStatus(code)
+	return err
+}
+
+// AddressType is the kind of address of a contact
+type AddressType string
+
+// Address types
+const (
+	AddressTypePOBox    AddressType = "POBOX"
+	AddressTypeStreet   AddressType = "STREET"
+	AddressTypeDelivery AddressType = "DELIVERY"
+)
+
+func (t AddressType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the address types above
+func (t AddressType) IsKnown() bool {
+	return knownCode(string(t), "POBOX", "STREET", "DELIVERY")
+}
+
+// UnmarshalJSON keeps unknown address types, see OnUnknownCode
+func (t *AddressType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "AddressType", func(c string) bool { return AddressType(c).IsKnown() })
+	*t = AddressType(code)
+	return err
+}
+
+// PhoneType is the kind of phone number of a contact
+type PhoneType string
+
+// Phone types
+const (
+	PhoneTypeDefault PhoneType = "DEFAULT"
+	PhoneTypeDDI     PhoneType = "DDI"
+	PhoneTypeMobile  PhoneType = "MOBILE"
+	PhoneTypeFax     PhoneType = "FAX"
+)
+
+func (t PhoneType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether t is one of the phone types above
+func (t PhoneType) IsKnown() bool {
+	return knownCode(string(t), "DEFAULT", "DDI", "MOBILE", "FAX")
+}
+
+// UnmarshalJSON keeps unknown phone types, see OnUnknownCode
+func (t *PhoneType) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "PhoneType", func(c string) bool { return PhoneType(c).IsKnown() })
+	*t = PhoneType(code)
+	return err
+}
+
+func (s InvoiceStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the invoice statuses
+func (s InvoiceStatus) IsKnown() bool {
+	return knownCode(string(s), "DRAFT", "SUBMITTED", "AUTHORISED", "PAID", "VOIDED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown invoice statuses, see OnUnknownCode
+func (s *InvoiceStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "InvoiceStatus", func(c string) bool { return InvoiceStatus(c).IsKnown() })
+	*s = InvoiceStatus(code)
+	return err
+}
+
+func (s CreditNoteStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the credit note statuses
+func (s CreditNoteStatus) IsKnown() bool {
+	return knownCode(string(s), "DRAFT", "SUBMITTED", "AUTHORISED", "PAID", "VOIDED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown credit note statuses, see OnUnknownCode
+func (s *CreditNoteStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "CreditNoteStatus", func(c string) bool { return CreditNoteStatus(c).IsKnown() })
+	*s = CreditNoteStatus(code)
+	return err
+}
+
+func (s PurchaseOrderStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the purchase order statuses
+func (s PurchaseOrderStatus) IsKnown() bool {
+	return knownCode(string(s), "DRAFT", "SUBMITTED", "AUTHORISED", "BILLED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown purchase order statuses, see OnUnknownCode
+func (s *PurchaseOrderStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "PurchaseOrderStatus", func(c string) bool { return PurchaseOrderStatus(c).IsKnown() })
+	*s = PurchaseOrderStatus(code)
+	return err
+}
+
+func (s ExpenseClaimStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the expense claim statuses
+func (s ExpenseClaimStatus) IsKnown() bool {
+	return knownCode(string(s), "SUBMITTED", "AUTHORISED", "PAID", "VOIDED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown expense claim statuses, see OnUnknownCode
+func (s *ExpenseClaimStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "ExpenseClaimStatus", func(c string) bool { return ExpenseClaimStatus(c).IsKnown() })
+	*s = ExpenseClaimStatus(code)
+	return err
+}
+
+func (s BankTransactionStatus) String() string {
+	return string(s)
+}
+
+// IsKnown reports whether s is one of the bank transaction statuses
+func (s BankTransactionStatus) IsKnown() bool {
+	return knownCode(string(s), "AUTHORISED", "DELETED")
+}
+
+// UnmarshalJSON keeps unknown bank transaction statuses, see OnUnknownCode
+func (s *BankTransactionStatus) UnmarshalJSON(data []byte) error {
+	code, err := unmarshalCode(data, "BankTransactionStatus", func(c string) bool { return BankTransactionStatus(c).IsKnown() })
+	*s = BankTransactionStatus(code)
+	return err
+}
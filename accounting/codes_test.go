@@ -0,0 +1,53 @@
+package accounting
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CodeHelpers(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.True(InvoiceTypeAccRec.IsReceivable())
+	a.False(InvoiceTypeAccPay.IsReceivable())
+	a.Equal(CreditNoteTypeAccPayCredit, InvoiceTypeAccPay.CreditNoteType())
+	a.Equal(InvoiceTypeAccRec, CreditNoteTypeAccRecCredit.InvoiceType())
+	a.True(BankTransactionTypeSpendOverpayment.IsSpend())
+	a.True(BankTransactionTypeSpendOverpayment.IsOverpayment())
+	a.False(BankTransactionTypeReceive.IsPrepayment())
+	a.Equal(AccountClassExpense, AccountTypeDirectCosts.Class())
+	a.True(AccountTypeSales.Class().IsProfitAndLoss())
+	a.True(AccountTypeCurrentLiability.Class().IsBalanceSheet())
+	a.Equal(AccountClass(""), AccountType("NEWTYPE").Class())
+	a.True(TaxType("TAX002").IsKnown())
+	a.True(TaxType("TAX002").IsCustom())
+	a.False(TaxType("NEWTAX").IsKnown())
+	a.True(PaymentTypeARCreditPayment.IsReceivable())
+	a.False(PaymentTypeAPOverpaymentPayment.IsReceivable())
+	a.False(LineAmountTypeNoTax.HasTax())
+	a.Equal("ACCREC", InvoiceTypeAccRec.String())
+}
+
+func Test_CodeUnmarshal(t *testing.T) {
+	a := assert.New(t)
+
+	unknown := []string{}
+	OnUnknownCode = func(codeList string, code string) {
+		unknown = append(unknown, codeList+" "+code)
+	}
+	defer func() { OnUnknownCode = nil }()
+
+	invoice := Invoice{}
+	err := json.Unmarshal([]byte(`{"Type":"ACCREC","Status":"ARCHIVED","LineAmountTypes":null,"LineItems":[{"TaxType":"TAX001"}]}`), &invoice)
+	a.Nil(err)
+	a.Equal(InvoiceTypeAccRec, invoice.Type)
+	a.Equal(InvoiceStatus("ARCHIVED"), invoice.Status)
+	a.Equal(LineAmountType(""), invoice.LineAmountTypes)
+	a.Equal([]string{"InvoiceStatus ARCHIVED"}, unknown)
+
+	err = json.Unmarshal([]byte(`{"Type":1}`), &invoice)
+	a.EqualError(err, "InvoiceType must be a string, not 1")
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -25,7 +24,7 @@ type Contact struct {
 	AccountNumber string `json:"AccountNumber,omitempty" xml:"AccountNumber,omitempty"`
 
 	// Current status of a contact – see contact status types
-	ContactStatus string `json:"ContactStatus,omitempty" xml:"ContactStatus,omitempty"`
+	ContactStatus ContactStatus `json:"ContactStatus,omitempty" xml:"ContactStatus,omitempty"`
 
 	// Full name of contact/organisation (max length = 255)
 	Name string `json:"Name,omitempty" xml:"Name,omitempty"`
@@ -52,10 +51,10 @@ type Contact struct {
 	TaxNumber string `json:"TaxNumber,omitempty" xml:"TaxNumber,omitempty"`
 
 	// Default tax type used for contact on AR Contacts
-	AccountsReceivableTaxType string `json:"AccountsReceivableTaxType,omitempty" xml:"AccountsReceivableTaxType,omitempty"`
+	AccountsReceivableTaxType TaxType `json:"AccountsReceivableTaxType,omitempty" xml:"AccountsReceivableTaxType,omitempty"`
 
 	// Default tax type used for contact on AP Contacts
-	AccountsPayableTaxType string `json:"AccountsPayableTaxType,omitempty" xml:"AccountsPayableTaxType,omitempty"`
+	AccountsPayableTaxType TaxType `json:"AccountsPayableTaxType,omitempty" xml:"AccountsPayableTaxType,omitempty"`
 
 	// Store certain address types for a contact – see address types
 	Addresses *[]Address `json:"Addresses,omitempty" xml:"Addresses>Address,omitempty"`
@@ -94,7 +93,7 @@ type Contact struct {
 	TrackingCategoryOption string `json:"TrackingCategoryOption,omitempty" xml:"TrackingCategoryOption,omitempty"`
 
 	// UTC timestamp of last update to contact
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// Displays which contact groups a contact is included in
 	ContactGroups *[]ContactGroup `json:"ContactGroups,omitempty" xml:"ContactGroups>ContactGroup,omitempty"`
@@ -134,20 +133,6 @@ type Balance struct {
 	Overdue     decimal.Decimal `json:"Overdue,omitempty" xml:"Overdue,omitempty"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (c *Contacts) convertDates() error {
-	var err error
-	for n := len(c.Contacts) - 1; n >= 0; n-- {
-		c.Contacts[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(c.Contacts[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalContact(contactResponseBytes []byte) (*Contacts, error) {
 	var contactResponse *Contacts
 	err := json.Unmarshal(contactResponseBytes, &contactResponse)
@@ -155,11 +140,6 @@ func unmarshalContact(contactResponseBytes []byte) (*Contacts, error) {
 		return nil, err
 	}
 
-	err = contactResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return contactResponse, err
 }
 
@@ -232,6 +212,67 @@ func FindContacts(ctx context.Context, provider xerogolang.IProvider, session go
 	return FindContactsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// ContactsOptions are the typed filters accepted by FindContactsWithOptions
+type ContactsOptions struct {
+	ListOptions
+
+	// Only return contacts with these ContactIDs
+	IDs []string
+
+	// Also return archived contacts
+	IncludeArchived bool
+
+	// Return a lightweight version of each contact - cannot be combined with Page
+	SummaryOnly bool
+
+	// Search Name, FirstName, LastName, ContactNumber and EmailAddress for this term
+	SearchTerm string
+}
+
+func (o *ContactsOptions) querystringParameters() ([]map[string]string, error) {
+	if o.SummaryOnly && o.Page > 0 {
+		return nil, ErrSummaryOnlyWithPage
+	}
+	if err := validateIDs("IDs", o.IDs); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addFlag(params, "includeArchived", o.IncludeArchived)
+	addFlag(params, "summaryOnly", o.SummaryOnly)
+	if o.SearchTerm != "" {
+		params["searchTerm"] = o.SearchTerm
+	}
+
+	return splitListFilters(o.Page, params, listFilter{name: "IDs", values: o.IDs})
+}
+
+// FindContactsWithOptions will get all Contacts matching the given options.
+// A long IDs list is split across several requests and the results joined
+func FindContactsWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *ContactsOptions) (*Contacts, error) {
+	if options == nil {
+		options = &ContactsOptions{}
+	}
+
+	paramSets, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := &Contacts{
+		Contacts: []Contact{},
+	}
+	for _, querystringParameters := range paramSets {
+		r, err := FindContactsModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+		if err != nil {
+			return nil, err
+		}
+		contacts.Contacts = append(contacts.Contacts, r.Contacts...)
+	}
+
+	return contacts, nil
+}
+
 // FindContact will get a single Contact - ContactID can be a GUID for an Contact or an Contact number
 func FindContact(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string) (*Contacts, error) {
 	additionalHeaders := map[string]string{
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,7 +16,7 @@ import (
 type CreditNote struct {
 
 	// See Credit Note Types
-	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
+	Type CreditNoteType `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -25,13 +24,13 @@ type CreditNote struct {
 	// The date the credit note is issued YYYY-MM-DD.
 	// If the Date element is not specified then it will default
 	// to the current date based on the timezone setting of the organisation
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// See Credit Note Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status CreditNoteStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// See Invoice Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// See Invoice Line Items
 	LineItems []LineItem `json:"LineItems,omitempty" xml:"LineItems>LineItem,omitempty"`
@@ -46,13 +45,13 @@ type CreditNote struct {
 	Total *decimal.Decimal `json:"Total,omitempty" xml:"Total,omitempty"`
 
 	// UTC timestamp of last update to the credit note
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// Currency used for the Credit Note
 	CurrencyCode string `json:"CurrencyCode,omitempty" xml:"CurrencyCode,omitempty"`
 
 	// Date when credit note was fully paid(UTC format)
-	FullyPaidOnDate string `json:"FullyPaidOnDate,omitempty" xml:"-"`
+	FullyPaidOnDate xerogolang.Date `json:"FullyPaidOnDate,omitzero" xml:"-"`
 
 	// Xero generated unique identifier
 	CreditNoteID string `json:"CreditNoteID,omitempty" xml:"CreditNoteID,omitempty"`
@@ -87,20 +86,6 @@ type CreditNotes struct {
 	CreditNotes []CreditNote `json:"CreditNotes" xml:"CreditNote"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (c *CreditNotes) convertDates() error {
-	var err error
-	for n := len(c.CreditNotes) - 1; n >= 0; n-- {
-		c.CreditNotes[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(c.CreditNotes[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalCreditNote(creditNoteResponseBytes []byte) (*CreditNotes, error) {
 	var creditNoteResponse *CreditNotes
 	err := json.Unmarshal(creditNoteResponseBytes, &creditNoteResponse)
@@ -108,11 +93,6 @@ func unmarshalCreditNote(creditNoteResponseBytes []byte) (*CreditNotes, error) {
 		return nil, err
 	}
 
-	err = creditNoteResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return creditNoteResponse, err
 }
 
@@ -185,6 +165,39 @@ func FindCreditNotes(ctx context.Context, provider xerogolang.IProvider, session
 	return FindCreditNotesModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// CreditNotesOptions are the typed filters accepted by FindCreditNotesWithOptions
+type CreditNotesOptions struct {
+	ListOptions
+
+	// Decimal places for unit amounts - 2 or 4
+	UnitDP int
+}
+
+func (o *CreditNotesOptions) querystringParameters() (map[string]string, error) {
+	if err := validateUnitDP(o.UnitDP); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addUnitDP(params, o.UnitDP)
+
+	return params, nil
+}
+
+// FindCreditNotesWithOptions will get all Credit Notes matching the given options
+func FindCreditNotesWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *CreditNotesOptions) (*CreditNotes, error) {
+	if options == nil {
+		options = &CreditNotesOptions{}
+	}
+
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindCreditNotesModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+}
+
 // FindCreditNote will get a single creditNote - creditNoteID can be a GUID for a creditNote or a creditNote number
 func FindCreditNote(ctx context.Context, provider xerogolang.IProvider, session goth.Session, creditNoteID string) (*CreditNotes, error) {
 	additionalHeaders := map[string]string{
@@ -199,6 +212,49 @@ func FindCreditNote(ctx context.Context, provider xerogolang.IProvider, session
 	return unmarshalCreditNote(creditNoteResponseBytes)
 }
 
+// Allocate allocates a creditNote to one or more invoices. Unlike overpayments and prepayments
+// a creditNote allocation needs a Date, so the allocations are sent as json
+func (c *CreditNotes) Allocate(ctx context.Context, provider xerogolang.IProvider, session goth.Session, allocations Allocations) (*Allocations, error) {
+	additionalHeaders := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	body, err := json.MarshalIndent(allocations, "  ", "	")
+	if err != nil {
+		return nil, err
+	}
+
+	allocationResponseBytes, err := provider.Create(ctx, session, "CreditNotes/"+c.CreditNotes[0].CreditNoteID+"/Allocations", additionalHeaders, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var allocationResponse *Allocations
+	err = json.Unmarshal(allocationResponseBytes, &allocationResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return allocationResponse, nil
+}
+
+// RemoveAllocation removes a single allocation from a creditNote and returns the updated creditNote
+// allocationID must be the GUID of an allocation on the creditNote
+func (c *CreditNotes) RemoveAllocation(ctx context.Context, provider xerogolang.IProvider, session goth.Session, allocationID string) (*CreditNotes, error) {
+	additionalHeaders := map[string]string{
+		"Accept": "application/json",
+	}
+
+	_, err := provider.Remove(ctx, session, "CreditNotes/"+c.CreditNotes[0].CreditNoteID+"/Allocations/"+allocationID, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	//the delete response only contains the removed allocation so we fetch the creditNote again to get its new RemainingCredit
+	return FindCreditNote(ctx, provider, session, c.CreditNotes[0].CreditNoteID)
+}
+
 // GenerateExampleCreditNote Creates an Example creditNote
 func GenerateExampleCreditNote() *CreditNotes {
 	lineItem := LineItem{
@@ -213,7 +269,7 @@ func GenerateExampleCreditNote() *CreditNotes {
 		Contact: Contact{
 			Name: "George Costanza",
 		},
-		Date:            helpers.TodayRFC3339(),
+		Date:            xerogolang.Today(),
 		LineAmountTypes: "Exclusive",
 		LineItems:       []LineItem{},
 	}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -27,10 +26,10 @@ type ExpenseClaim struct {
 	Payments *[]Payment `json:"Payments,omitempty" xml:"Payments>Payment,omitempty"`
 
 	// Current status of an expense claim – see status types
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status ExpenseClaimStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// The total of an expense claim being paid
 	Total decimal.Decimal `json:"Total,omitempty" xml:"Total,omitempty"`
@@ -42,10 +41,10 @@ type ExpenseClaim struct {
 	AmountPaid decimal.Decimal `json:"AmountPaid,omitempty" xml:"AmountPaid,omitempty"`
 
 	// The date when the expense claim is due to be paid YYYY-MM-DD
-	PaymentDueDate string `json:"PaymentDueDate,omitempty" xml:"PaymentDueDate,omitempty"`
+	PaymentDueDate xerogolang.Date `json:"PaymentDueDate,omitzero" xml:"PaymentDueDate,omitempty"`
 
 	// The date the expense claim will be reported in Xero YYYY-MM-DD
-	ReportingDate string `json:"ReportingDate,omitempty" xml:"ReportingDate,omitempty"`
+	ReportingDate xerogolang.Date `json:"ReportingDate,omitzero" xml:"ReportingDate,omitempty"`
 
 	// The Xero identifier for the Receipt e.g. e59a2c7f-1306-4078-a0f3-73537afcbba9
 	ReceiptID string `json:"ReceiptID" xml:"ReceiptID"`
@@ -56,20 +55,6 @@ type ExpenseClaims struct {
 	ExpenseClaims []ExpenseClaim `json:"ExpenseClaims" xml:"ExpenseClaim"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (e *ExpenseClaims) convertDates() error {
-	var err error
-	for n := len(e.ExpenseClaims) - 1; n >= 0; n-- {
-		e.ExpenseClaims[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(e.ExpenseClaims[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalExpenseClaim(expenseClaimResponseBytes []byte) (*ExpenseClaims, error) {
 	var expenseClaimResponse *ExpenseClaims
 	err := json.Unmarshal(expenseClaimResponseBytes, &expenseClaimResponse)
@@ -77,11 +62,6 @@ func unmarshalExpenseClaim(expenseClaimResponseBytes []byte) (*ExpenseClaims, er
 		return nil, err
 	}
 
-	err = expenseClaimResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return expenseClaimResponse, err
 }
 
@@ -0,0 +1,426 @@
+package accounting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+)
+
+// FieldChange is a field that was changed in Xero after the caller read the record
+type FieldChange struct {
+	// Field is the path to the field e.g. LineItems[0].Description
+	Field string
+
+	// Read is the value the caller read, Current the value in Xero. Either is nil when the field is missing
+	Read    interface{}
+	Current interface{}
+}
+
+// ConflictError is returned by the UpdateIfUnchanged methods when the record was changed in Xero after it was read
+type ConflictError struct {
+	// Resource is the endpoint of the record e.g. Invoices
+	Resource string
+	ID       string
+
+	// ReadUpdatedDateUTC is the version the caller read, CurrentUpdatedDateUTC the version in Xero
+	ReadUpdatedDateUTC    xerogolang.DateTime
+	CurrentUpdatedDateUTC xerogolang.DateTime
+
+	// Changes lists the fields where the record the caller read differs from the record in Xero
+	Changes []FieldChange
+}
+
+func (e *ConflictError) Error() string {
+	fields := make([]string, len(e.Changes))
+	for n, change := range e.Changes {
+		fields[n] = change.Field
+	}
+	return fmt.Sprintf("%s/%s was changed at %s after it was read at %s (differs in %s)",
+		e.Resource, e.ID, e.CurrentUpdatedDateUTC, e.ReadUpdatedDateUTC, strings.Join(fields, ", "))
+}
+
+// diffFields compares two records field by field as they are sent to Xero. UpdatedDateUTC is left out
+func diffFields(read interface{}, current interface{}) ([]FieldChange, error) {
+	r, err := jsonValue(read)
+	if err != nil {
+		return nil, err
+	}
+	c, err := jsonValue(current)
+	if err != nil {
+		return nil, err
+	}
+	if m, ok := r.(map[string]interface{}); ok {
+		delete(m, "UpdatedDateUTC")
+	}
+	if m, ok := c.(map[string]interface{}); ok {
+		delete(m, "UpdatedDateUTC")
+	}
+	changes := []FieldChange{}
+	diffValues("", r, c, &changes)
+	return changes, nil
+}
+
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value interface{}
+	err = decoder.Decode(&value)
+	return value, err
+}
+
+func diffValues(path string, read interface{}, current interface{}, changes *[]FieldChange) {
+	switch r := read.(type) {
+	case map[string]interface{}:
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := []string{}
+		for key := range r {
+			keys = append(keys, key)
+		}
+		for key := range c {
+			if _, ok := r[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+			diffValues(field, r[key], c[key], changes)
+		}
+		return
+	case []interface{}:
+		c, ok := current.([]interface{})
+		if !ok {
+			break
+		}
+		for n := 0; n < len(r) || n < len(c); n++ {
+			var rv, cv interface{}
+			if n < len(r) {
+				rv = r[n]
+			}
+			if n < len(c) {
+				cv = c[n]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, n), rv, cv, changes)
+		}
+		return
+	}
+	if !reflect.DeepEqual(read, current) {
+		*changes = append(*changes, FieldChange{Field: path, Read: read, Current: current})
+	}
+}
+
+// checkUnchanged re-reads every record of a collection with find and returns a ConflictError when one of them
+// was changed since the caller read it. Each record must be in the records the caller read, matched by ID
+func checkUnchanged[T any](resource string, records []T, read []T, version func(T) (string, xerogolang.DateTime), find func(id string) ([]T, error)) error {
+	if len(records) == 0 {
+		return fmt.Errorf("%s: there is no record to update", resource)
+	}
+	for _, record := range records {
+		id, _ := version(record)
+		if id == "" {
+			return fmt.Errorf("%s: the ID of the record to update is not set", resource)
+		}
+		readRecord, found := findVersion(read, id, version)
+		if !found {
+			return fmt.Errorf("%s/%s: the record is not in the records read, read the record before updating it", resource, id)
+		}
+		_, readUpdatedDateUTC := version(readRecord)
+		if readUpdatedDateUTC.IsZero() {
+			return fmt.Errorf("%s/%s: UpdatedDateUTC is not set, read the record before updating it", resource, id)
+		}
+		current, err := find(id)
+		if err != nil {
+			return err
+		}
+		currentRecord, found := findVersion(current, id, version)
+		if !found {
+			return fmt.Errorf("%s/%s was not found", resource, id)
+		}
+		_, currentUpdatedDateUTC := version(currentRecord)
+		if readUpdatedDateUTC.Equal(currentUpdatedDateUTC.Time) {
+			continue
+		}
+		changes, err := diffFields(readRecord, currentRecord)
+		if err != nil {
+			return err
+		}
+		return &ConflictError{
+			Resource:              resource,
+			ID:                    id,
+			ReadUpdatedDateUTC:    readUpdatedDateUTC,
+			CurrentUpdatedDateUTC: currentUpdatedDateUTC,
+			Changes:               changes,
+		}
+	}
+	return nil
+}
+
+// findVersion returns the record with an ID from a collection
+func findVersion[T any](records []T, id string, version func(T) (string, xerogolang.DateTime)) (T, bool) {
+	for _, record := range records {
+		if recordID, _ := version(record); recordID == id {
+			return record, true
+		}
+	}
+	var none T
+	return none, false
+}
+
+// UpdateIfUnchanged updates the invoices only when none was changed in Xero since the caller read them into read,
+// and returns a *ConflictError otherwise. The check and the update are separate calls, so it narrows rather than
+// closes the window
+func (i *Invoices) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Invoices) (*Invoices, error) {
+	if read == nil {
+		read = &Invoices{}
+	}
+	err := checkUnchanged("Invoices", i.Invoices, read.Invoices, func(invoice Invoice) (string, xerogolang.DateTime) {
+		return invoice.InvoiceID, invoice.UpdatedDateUTC
+	}, func(id string) ([]Invoice, error) {
+		current, err := FindInvoice(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Invoices, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the credit notes only when none was changed in Xero since the caller read them into read
+func (c *CreditNotes) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *CreditNotes) (*CreditNotes, error) {
+	if read == nil {
+		read = &CreditNotes{}
+	}
+	err := checkUnchanged("CreditNotes", c.CreditNotes, read.CreditNotes, func(creditNote CreditNote) (string, xerogolang.DateTime) {
+		return creditNote.CreditNoteID, creditNote.UpdatedDateUTC
+	}, func(id string) ([]CreditNote, error) {
+		current, err := FindCreditNote(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.CreditNotes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the contacts only when none was changed in Xero since the caller read them into read
+func (c *Contacts) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Contacts) (*Contacts, error) {
+	if read == nil {
+		read = &Contacts{}
+	}
+	err := checkUnchanged("Contacts", c.Contacts, read.Contacts, func(contact Contact) (string, xerogolang.DateTime) {
+		return contact.ContactID, contact.UpdatedDateUTC
+	}, func(id string) ([]Contact, error) {
+		current, err := FindContact(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Contacts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the accounts only when none was changed in Xero since the caller read them into read
+func (a *Accounts) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Accounts) (*Accounts, error) {
+	if read == nil {
+		read = &Accounts{}
+	}
+	err := checkUnchanged("Accounts", a.Accounts, read.Accounts, func(account Account) (string, xerogolang.DateTime) {
+		return account.AccountID, account.UpdatedDateUTC
+	}, func(id string) ([]Account, error) {
+		current, err := FindAccount(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Accounts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the bank transactions only when none was changed in Xero since the caller read them into read
+func (b *BankTransactions) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *BankTransactions) (*BankTransactions, error) {
+	if read == nil {
+		read = &BankTransactions{}
+	}
+	err := checkUnchanged("BankTransactions", b.BankTransactions, read.BankTransactions, func(bankTransaction BankTransaction) (string, xerogolang.DateTime) {
+		return bankTransaction.BankTransactionID, bankTransaction.UpdatedDateUTC
+	}, func(id string) ([]BankTransaction, error) {
+		current, err := FindBankTransaction(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.BankTransactions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the expense claims only when none was changed in Xero since the caller read them into read
+func (e *ExpenseClaims) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *ExpenseClaims) (*ExpenseClaims, error) {
+	if read == nil {
+		read = &ExpenseClaims{}
+	}
+	err := checkUnchanged("ExpenseClaims", e.ExpenseClaims, read.ExpenseClaims, func(expenseClaim ExpenseClaim) (string, xerogolang.DateTime) {
+		return expenseClaim.ExpenseClaimID, expenseClaim.UpdatedDateUTC
+	}, func(id string) ([]ExpenseClaim, error) {
+		current, err := FindExpenseClaim(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.ExpenseClaims, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the items only when none was changed in Xero since the caller read them into read
+func (i *Items) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Items) (*Items, error) {
+	if read == nil {
+		read = &Items{}
+	}
+	err := checkUnchanged("Items", i.Items, read.Items, func(item Item) (string, xerogolang.DateTime) {
+		return item.ItemID, item.UpdatedDateUTC
+	}, func(id string) ([]Item, error) {
+		current, err := FindItem(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Items, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the linked transactions only when none was changed in Xero since the caller read them into read
+func (l *LinkedTransactions) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *LinkedTransactions) (*LinkedTransactions, error) {
+	if read == nil {
+		read = &LinkedTransactions{}
+	}
+	err := checkUnchanged("LinkedTransactions", l.LinkedTransactions, read.LinkedTransactions, func(linkedTransaction LinkedTransaction) (string, xerogolang.DateTime) {
+		return linkedTransaction.LinkedTransactionID, linkedTransaction.UpdatedDateUTC
+	}, func(id string) ([]LinkedTransaction, error) {
+		current, err := FindLinkedTransaction(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.LinkedTransactions, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the manual journals only when none was changed in Xero since the caller read them into read
+func (m *ManualJournals) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *ManualJournals) (*ManualJournals, error) {
+	if read == nil {
+		read = &ManualJournals{}
+	}
+	err := checkUnchanged("ManualJournals", m.ManualJournals, read.ManualJournals, func(manualJournal ManualJournal) (string, xerogolang.DateTime) {
+		return manualJournal.ManualJournalID, manualJournal.UpdatedDateUTC
+	}, func(id string) ([]ManualJournal, error) {
+		current, err := FindManualJournal(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.ManualJournals, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the payments only when none was changed in Xero since the caller read them into read
+func (p *Payments) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Payments) (*Payments, error) {
+	if read == nil {
+		read = &Payments{}
+	}
+	err := checkUnchanged("Payments", p.Payments, read.Payments, func(payment Payment) (string, xerogolang.DateTime) {
+		return payment.PaymentID, payment.UpdatedDateUTC
+	}, func(id string) ([]Payment, error) {
+		current, err := FindPayment(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Payments, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the purchase orders only when none was changed in Xero since the caller read them into read
+func (p *PurchaseOrders) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *PurchaseOrders) (*PurchaseOrders, error) {
+	if read == nil {
+		read = &PurchaseOrders{}
+	}
+	err := checkUnchanged("PurchaseOrders", p.PurchaseOrders, read.PurchaseOrders, func(purchaseOrder PurchaseOrder) (string, xerogolang.DateTime) {
+		return purchaseOrder.PurchaseOrderID, purchaseOrder.UpdatedDateUTC
+	}, func(id string) ([]PurchaseOrder, error) {
+		current, err := FindPurchaseOrder(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.PurchaseOrders, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.Update(ctx, provider, session)
+}
+
+// UpdateIfUnchanged updates the receipts only when none was changed in Xero since the caller read them into read
+func (r *Receipts) UpdateIfUnchanged(ctx context.Context, provider xerogolang.IProvider, session goth.Session, read *Receipts) (*Receipts, error) {
+	if read == nil {
+		read = &Receipts{}
+	}
+	err := checkUnchanged("Receipts", r.Receipts, read.Receipts, func(receipt Receipt) (string, xerogolang.DateTime) {
+		return receipt.ReceiptID, receipt.UpdatedDateUTC
+	}, func(id string) ([]Receipt, error) {
+		current, err := FindReceipt(ctx, provider, session, id)
+		if err != nil {
+			return nil, err
+		}
+		return current.Receipts, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.Update(ctx, provider, session)
+}
@@ -0,0 +1,120 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRequest is a call made to a testProvider
+type testRequest struct {
+	Method   string
+	Endpoint string
+	Body     string
+
+	// IdempotencyKey set on the context of the call
+	IdempotencyKey string
+}
+
+// testProvider answers calls with canned responses keyed by method and endpoint, e.g. "GET Invoices/1"
+type testProvider struct {
+	responses map[string]string
+	requests  []testRequest
+}
+
+func (p *testProvider) respond(ctx context.Context, method string, endpoint string, body []byte) ([]byte, error) {
+	key, _ := xerogolang.IdempotencyKey(ctx)
+	p.requests = append(p.requests, testRequest{Method: method, Endpoint: endpoint, Body: string(body), IdempotencyKey: key})
+	response, ok := p.responses[method+" "+endpoint]
+	if !ok {
+		return nil, fmt.Errorf("no response for %s %s", method, endpoint)
+	}
+	return []byte(response), nil
+}
+
+func (p *testProvider) Find(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, querystringParameters map[string]string) ([]byte, error) {
+	return p.respond(ctx, "GET", endpoint, nil)
+}
+
+func (p *testProvider) Create(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return p.respond(ctx, "PUT", endpoint, body)
+}
+
+func (p *testProvider) Update(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return p.respond(ctx, "POST", endpoint, body)
+}
+
+func (p *testProvider) Remove(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string) ([]byte, error) {
+	return p.respond(ctx, "DELETE", endpoint, nil)
+}
+
+func Test_InvoicesUpdateIfUnchanged(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	current := `{"Invoices":[{"InvoiceID":"inv-1","Type":"ACCREC","Reference":"PO-9","Contact":{"Name":"City Limousines"},
+		"LineItems":[{"Description":"Consulting","LineAmount":100}],"UpdatedDateUTC":"/Date(1711886400000+0000)/"}]}`
+	provider := &testProvider{responses: map[string]string{
+		"GET Invoices/inv-1":  current,
+		"POST Invoices/inv-1": current,
+		"GET Invoices/inv-2":  `{"Invoices":[{"InvoiceID":"inv-2","UpdatedDateUTC":"/Date(1711886400000+0000)/"}]}`,
+	}}
+
+	invoice := Invoice{
+		InvoiceID:      "inv-1",
+		Type:           "ACCREC",
+		Reference:      "PO-9",
+		Contact:        Contact{Name: "City Limousines"},
+		LineItems:      []LineItem{{Description: "Consulting", LineAmount: decimal.NewFromInt(100)}},
+		UpdatedDateUTC: xerogolang.NewDateTime(time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)),
+	}
+	read := &Invoices{Invoices: []Invoice{invoice}}
+	invoices := &Invoices{Invoices: []Invoice{invoice}}
+	invoices.Invoices[0].Reference = "PO-8"
+	_, err := invoices.UpdateIfUnchanged(context.Background(), provider, nil, read)
+	a.NoError(err)
+	a.Len(provider.requests, 2)
+
+	//the changes are what changed in Xero since the caller read the invoice, not the edits being saved
+	read.Invoices[0].UpdatedDateUTC = xerogolang.NewDateTime(time.Date(2024, 3, 30, 9, 0, 0, 0, time.UTC))
+	read.Invoices[0].Reference = "PO-7"
+	_, err = invoices.UpdateIfUnchanged(context.Background(), provider, nil, read)
+	conflict, ok := err.(*ConflictError)
+	a.True(ok)
+	a.Equal("Invoices", conflict.Resource)
+	a.Equal("2024-03-30T09:00:00", conflict.ReadUpdatedDateUTC.String())
+	a.Equal("2024-03-31T12:00:00", conflict.CurrentUpdatedDateUTC.String())
+	a.Equal([]FieldChange{{Field: "Reference", Read: "PO-7", Current: "PO-9"}}, conflict.Changes)
+	a.Len(provider.requests, 3)
+
+	//every record of the collection is checked
+	read.Invoices = []Invoice{invoice, {InvoiceID: "inv-2", UpdatedDateUTC: xerogolang.NewDateTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))}}
+	invoices.Invoices = append(invoices.Invoices, Invoice{InvoiceID: "inv-2"})
+	_, err = invoices.UpdateIfUnchanged(context.Background(), provider, nil, read)
+	conflict, ok = err.(*ConflictError)
+	a.True(ok)
+	a.Equal("inv-2", conflict.ID)
+	a.Len(provider.requests, 5)
+
+	_, err = invoices.UpdateIfUnchanged(context.Background(), provider, nil, nil)
+	a.EqualError(err, "Invoices/inv-1: the record is not in the records read, read the record before updating it")
+	read.Invoices[0].UpdatedDateUTC = xerogolang.DateTime{}
+	_, err = invoices.UpdateIfUnchanged(context.Background(), provider, nil, read)
+	a.EqualError(err, "Invoices/inv-1: UpdatedDateUTC is not set, read the record before updating it")
+	a.Len(provider.requests, 5)
+	_, err = (&Invoices{}).UpdateIfUnchanged(context.Background(), provider, nil, read)
+	a.EqualError(err, "Invoices: there is no record to update")
+	a.Len(provider.requests, 5)
+
+	provider.responses["GET Contacts/c-1"] = `{"Contacts":[]}`
+	contacts := &Contacts{Contacts: []Contact{{ContactID: "c-1", UpdatedDateUTC: invoice.UpdatedDateUTC}}}
+	_, err = contacts.UpdateIfUnchanged(context.Background(), provider, nil, contacts)
+	a.EqualError(err, "Contacts/c-1 was not found")
+	a.Len(provider.requests, 6)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // BankTransfer is a record of monies transferred from one bank account to another
@@ -16,7 +15,7 @@ type HistoryRecord struct {
 	Changes string `json:"Changes,omitempty" xml:"-"`
 
 	// UTC date that the history record was created
-	DateUTC string `json:"DateUTC,omitempty" xml:"-"`
+	DateUTC xerogolang.DateTime `json:"DateUTC,omitzero" xml:"-"`
 
 	// The user responsible for the change ("System Generated" when the change happens via API)
 	User string `json:"User,omitempty" xml:"-"`
@@ -30,19 +29,6 @@ type HistoryRecords struct {
 	HistoryRecords []HistoryRecord `json:"HistoryRecords" xml:"HistoryRecords"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (h *HistoryRecords) convertDates() error {
-	var err error
-	for n := len(h.HistoryRecords) - 1; n >= 0; n-- {
-		h.HistoryRecords[n].DateUTC, err = helpers.DotNetJSONTimeToRFC3339(h.HistoryRecords[n].DateUTC, false)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func unmarshalHistoryRecord(HistoryRecordResponseBytes []byte) (*HistoryRecords, error) {
 	var historyRecordResponse *HistoryRecords
 	err := json.Unmarshal(HistoryRecordResponseBytes, &historyRecordResponse)
@@ -50,11 +36,6 @@ func unmarshalHistoryRecord(HistoryRecordResponseBytes []byte) (*HistoryRecords,
 		return nil, err
 	}
 
-	err = historyRecordResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return historyRecordResponse, err
 }
 
@@ -3,18 +3,18 @@ package accounting
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
 // Invoice is an Accounts Payable or Accounts Recievable document in a Xero organisation
 type Invoice struct {
 	// See Invoice Types
-	Type string `json:"Type" xml:"Type"`
+	Type InvoiceType `json:"Type" xml:"Type"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -23,13 +23,13 @@ type Invoice struct {
 	LineItems []LineItem `json:"LineItems" xml:"LineItems>LineItem"`
 
 	// Date invoice was issued – YYYY-MM-DD. If the Date element is not specified it will default to the current date based on the timezone setting of the organisation
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// Date invoice is due – YYYY-MM-DD
-	DueDate string `json:"DueDateString,omitempty" xml:"DueDate,omitempty"`
+	DueDate xerogolang.Date `json:"DueDateString,omitzero" xml:"DueDate,omitempty"`
 
 	// Line amounts are exclusive of tax by default if you don’t specify this element. See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// ACCREC – Unique alpha numeric code identifying invoice (when missing will auto-generate from your Organisation Invoice Settings) (max length = 255)
 	InvoiceNumber string `json:"InvoiceNumber,omitempty" xml:"InvoiceNumber,omitempty"`
@@ -50,16 +50,16 @@ type Invoice struct {
 	CurrencyRate decimal.Decimal `json:"CurrencyRate,omitempty" xml:"CurrencyRate,omitempty"`
 
 	// See Invoice Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status InvoiceStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Boolean to set whether the invoice in the Xero app should be marked as “sent”. This can be set only on invoices that have been approved
 	SentToContact bool `json:"SentToContact,omitempty" xml:"SentToContact,omitempty"`
 
 	// Shown on sales invoices (Accounts Receivable) when this has been set
-	ExpectedPaymentDate string `json:"ExpectedPaymentDate,omitempty" xml:"ExpectedPaymentDate,omitempty"`
+	ExpectedPaymentDate xerogolang.Date `json:"ExpectedPaymentDate,omitzero" xml:"ExpectedPaymentDate,omitempty"`
 
 	// Shown on bills (Accounts Payable) when this has been set
-	PlannedPaymentDate string `json:"PlannedPaymentDate,omitempty" xml:"PlannedPaymentDate,omitempty"`
+	PlannedPaymentDate xerogolang.Date `json:"PlannedPaymentDate,omitzero" xml:"PlannedPaymentDate,omitempty"`
 
 	// Total of invoice excluding taxes
 	SubTotal decimal.Decimal `json:"SubTotal,omitempty" xml:"SubTotal,omitempty"`
@@ -95,13 +95,13 @@ type Invoice struct {
 	AmountPaid decimal.Decimal `json:"AmountPaid,omitempty" xml:"-"`
 
 	// The date the invoice was fully paid. Only returned on fully paid invoices
-	FullyPaidOnDate string `json:"FullyPaidOnDate,omitempty" xml:"-"`
+	FullyPaidOnDate xerogolang.Date `json:"FullyPaidOnDate,omitzero" xml:"-"`
 
 	// Sum of all credit notes, over-payments and pre-payments applied to invoice
 	AmountCredited decimal.Decimal `json:"AmountCredited,omitempty" xml:"-"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// Details of credit notes that have been applied to an invoice
 	CreditNotes *[]CreditNote `json:"CreditNotes,omitempty" xml:"-"`
@@ -116,20 +116,6 @@ var (
 	dayZero = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
 )
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (i *Invoices) convertDates() error {
-	var err error
-	for n := len(i.Invoices) - 1; n >= 0; n-- {
-		i.Invoices[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(i.Invoices[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalInvoice(invoiceResponseBytes []byte) (*Invoices, error) {
 	var invoiceResponse *Invoices
 	err := json.Unmarshal(invoiceResponseBytes, &invoiceResponse)
@@ -137,11 +123,6 @@ func unmarshalInvoice(invoiceResponseBytes []byte) (*Invoices, error) {
 		return nil, err
 	}
 
-	err = invoiceResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return invoiceResponse, err
 }
 
@@ -214,6 +195,103 @@ func FindInvoices(ctx context.Context, provider xerogolang.IProvider, session go
 	return FindInvoicesModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// InvoicesOptions are the typed filters accepted by FindInvoicesWithOptions
+type InvoicesOptions struct {
+	ListOptions
+
+	// Only return invoices with these InvoiceIDs
+	IDs []string
+
+	// Only return invoices with these InvoiceNumbers
+	InvoiceNumbers []string
+
+	// Only return invoices for these ContactIDs
+	ContactIDs []string
+
+	// Only return invoices with these Statuses e.g. DRAFT, AUTHORISED
+	Statuses []string
+
+	// Also return invoices for archived contacts
+	IncludeArchived bool
+
+	// Only return invoices created by your app
+	CreatedByMyApp bool
+
+	// Return a lightweight version of each invoice - cannot be combined with Page
+	SummaryOnly bool
+
+	// Search InvoiceNumber and Reference for this term
+	SearchTerm string
+
+	// Decimal places for unit amounts - 2 or 4
+	UnitDP int
+}
+
+func (o *InvoicesOptions) querystringParameters() ([]map[string]string, error) {
+	if o.SummaryOnly && o.Page > 0 {
+		return nil, ErrSummaryOnlyWithPage
+	}
+	if err := validateIDs("IDs", o.IDs); err != nil {
+		return nil, err
+	}
+	if err := validateIDs("ContactIDs", o.ContactIDs); err != nil {
+		return nil, err
+	}
+	if err := validateValues("InvoiceNumbers", o.InvoiceNumbers); err != nil {
+		return nil, err
+	}
+	if err := validateValues("Statuses", o.Statuses); err != nil {
+		return nil, err
+	}
+	if err := validateUnitDP(o.UnitDP); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addFlag(params, "includeArchived", o.IncludeArchived)
+	addFlag(params, "createdByMyApp", o.CreatedByMyApp)
+	addFlag(params, "summaryOnly", o.SummaryOnly)
+	if o.SearchTerm != "" {
+		params["searchTerm"] = o.SearchTerm
+	}
+	addUnitDP(params, o.UnitDP)
+	if len(o.Statuses) > 0 {
+		params["Statuses"] = strings.Join(o.Statuses, ",")
+	}
+
+	return splitListFilters(o.Page, params,
+		listFilter{name: "IDs", values: o.IDs},
+		listFilter{name: "InvoiceNumbers", values: o.InvoiceNumbers},
+		listFilter{name: "ContactIDs", values: o.ContactIDs},
+	)
+}
+
+// FindInvoicesWithOptions will get all Invoices matching the given options.
+// Long IDs, InvoiceNumbers and ContactIDs lists are split across several requests and the results joined
+func FindInvoicesWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *InvoicesOptions) (*Invoices, error) {
+	if options == nil {
+		options = &InvoicesOptions{}
+	}
+
+	paramSets, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	invoices := &Invoices{
+		Invoices: []Invoice{},
+	}
+	for _, querystringParameters := range paramSets {
+		r, err := FindInvoicesModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+		if err != nil {
+			return nil, err
+		}
+		invoices.Invoices = append(invoices.Invoices, r.Invoices...)
+	}
+
+	return invoices, nil
+}
+
 // FindInvoice will get a single invoice - invoiceID can be a GUID for an invoice or an invoice number
 func FindInvoice(ctx context.Context, provider xerogolang.IProvider, session goth.Session, invoiceID string) (*Invoices, error) {
 	additionalHeaders := map[string]string{
@@ -242,8 +320,8 @@ func GenerateExampleInvoice() *Invoices {
 		Contact: Contact{
 			Name: "George Costanza",
 		},
-		Date:            helpers.TodayRFC3339(),
-		DueDate:         helpers.FormatDate(time.Now().Add(720 * time.Hour)),
+		Date:            xerogolang.Today(),
+		DueDate:         xerogolang.Today().AddDays(30),
 		LineAmountTypes: "Exclusive",
 		LineItems:       []LineItem{},
 	}
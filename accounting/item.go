@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -52,7 +51,7 @@ type Item struct {
 	QuantityOnHand decimal.Decimal `json:"QuantityOnHand,omitempty" xml:"-"`
 
 	// Last modified date in UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// The Xero identifier for an Item
 	ItemID string `json:"ItemID,omitempty" xml:"ItemID,omitempty"`
@@ -75,21 +74,7 @@ type PurchaseAndSaleDetails struct {
 	COGSAccountCode string `json:"COGSAccountCode,omitempty" xml:"COGSAccountCode,omitempty"`
 
 	//Used as an override if the default Tax Code for the selected AccountCode is not correct - see TaxTypes.
-	TaxType string `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
-}
-
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (i *Items) convertDates() error {
-	var err error
-	for n := len(i.Items) - 1; n >= 0; n-- {
-		i.Items[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(i.Items[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	TaxType TaxType `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
 }
 
 func unmarshalItem(itemResponseBytes []byte) (*Items, error) {
@@ -99,11 +84,6 @@ func unmarshalItem(itemResponseBytes []byte) (*Items, error) {
 		return nil, err
 	}
 
-	err = itemResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return itemResponse, err
 }
 
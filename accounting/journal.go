@@ -3,11 +3,11 @@ package accounting
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // Journal is a record of a financial transaction in Xero
@@ -17,13 +17,13 @@ type Journal struct {
 	JournalID string `json:"JournalID,omitempty" xml:"JournalID,omitempty"`
 
 	// Date the journal was posted
-	JournalDate string `json:"JournalDate,omitempty" xml:"JournalDate,omitempty"`
+	JournalDate xerogolang.Date `json:"JournalDate,omitzero" xml:"JournalDate,omitempty"`
 
 	// Xero generated journal number
 	JournalNumber int `json:"JournalNumber,omitempty" xml:"JournalNumber,omitempty"`
 
 	// Created date UTC format
-	CreatedDateUTC string `json:"CreatedDateUTC,omitempty" xml:"CreatedDateUTC,omitempty"`
+	CreatedDateUTC xerogolang.DateTime `json:"CreatedDateUTC,omitzero" xml:"CreatedDateUTC,omitempty"`
 
 	//
 	Reference string `json:"Reference,omitempty" xml:"Reference,omitempty"`
@@ -43,24 +43,6 @@ type Journals struct {
 	Journals []Journal `json:"Journals,omitempty" xml:"Journal,omitempty"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (j *Journals) convertDates() error {
-	var err error
-	for n := len(j.Journals) - 1; n >= 0; n-- {
-		j.Journals[n].JournalDate, err = helpers.DotNetJSONTimeToRFC3339(j.Journals[n].JournalDate, false)
-		if err != nil {
-			return err
-		}
-		j.Journals[n].CreatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(j.Journals[n].CreatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalJournals(journalResponseBytes []byte) (*Journals, error) {
 	var journalResponse *Journals
 	err := json.Unmarshal(journalResponseBytes, &journalResponse)
@@ -68,11 +50,6 @@ func unmarshalJournals(journalResponseBytes []byte) (*Journals, error) {
 		return nil, err
 	}
 
-	err = journalResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return journalResponse, err
 }
 
@@ -107,6 +84,34 @@ func FindJournals(ctx context.Context, provider xerogolang.IProvider, session go
 	return FindJournalsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// EachJournal pages through all journals with a JournalNumber above offset, oldest to newest, and calls fn for each one.
+// Paging stops as soon as fn returns an error, which is then returned
+// additional querystringParameters such as paymentsOnly can be added as a map
+func EachJournal(ctx context.Context, provider xerogolang.IProvider, session goth.Session, offset int, querystringParameters map[string]string, fn func(Journal) error) error {
+	for {
+		params := map[string]string{}
+		for key, value := range querystringParameters {
+			params[key] = value
+		}
+		params["offset"] = strconv.Itoa(offset)
+
+		journals, err := FindJournals(ctx, provider, session, params)
+		if err != nil {
+			return err
+		}
+		for _, journal := range journals.Journals {
+			if err := fn(journal); err != nil {
+				return err
+			}
+			offset = journal.JournalNumber
+		}
+		//a page holds at most 100 journals so a short page is the last one
+		if len(journals.Journals) < 100 {
+			return nil
+		}
+	}
+}
+
 // FindJournal will get a single journal - journalID can be a GUID for an journal or an journal number
 func FindJournal(ctx context.Context, provider xerogolang.IProvider, session goth.Session, journalID string) (*Journals, error) {
 	additionalHeaders := map[string]string{
@@ -14,7 +14,7 @@ type JournalLine struct {
 	AccountCode string `json:"AccountCode" xml:"AccountCode"`
 
 	// See Accounts
-	AccountType string `json:"AccountType" xml:"AccountType"`
+	AccountType AccountType `json:"AccountType" xml:"AccountType"`
 
 	// See Accounts
 	AccountName string `json:"AccountName" xml:"AccountName"`
@@ -32,7 +32,7 @@ type JournalLine struct {
 	TaxAmount decimal.Decimal `json:"TaxAmount,omitempty" xml:"TaxAmount,omitempty"`
 
 	// Used as an override if the default Tax Code for the selected <AccountCode> is not correct – see TaxTypes.
-	TaxType string `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
+	TaxType TaxType `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
 
 	//see tax TaxTypes
 	TaxName string `json:"TaxName,omitempty" xml:"TaxName,omitempty"`
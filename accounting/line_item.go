@@ -23,7 +23,7 @@ type LineItem struct {
 	AccountCode string `json:"AccountCode,omitempty" xml:"AccountCode,omitempty"`
 
 	// Used as an override if the default Tax Code for the selected <AccountCode> is not correct – see TaxTypes.
-	TaxType string `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
+	TaxType TaxType `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
 
 	// The tax amount is auto calculated as a percentage of the line amount (see below) based on the tax rate. This value can be overriden if the calculated <TaxAmount> is not correct.
 	TaxAmount decimal.Decimal `json:"TaxAmount,omitempty" xml:"TaxAmount,omitempty"`
@@ -0,0 +1,300 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+)
+
+// ErrRemoveAllLineItems is returned when an update would remove every line of a document. Xero keeps the lines
+// of a document updated without any, so void or delete the document instead
+var ErrRemoveAllLineItems = errors.New("an update cannot remove every line item of a document")
+
+// LineItemChangeType says what an update does to a line item
+type LineItemChangeType string
+
+// Line item change types
+const (
+	LineItemAdded   LineItemChangeType = "added"
+	LineItemChanged LineItemChangeType = "changed"
+	LineItemRemoved LineItemChangeType = "removed"
+)
+
+// LineItemChange is a line item an update adds, changes or removes
+type LineItemChange struct {
+	Type LineItemChangeType
+
+	// Index of the line in the desired document, or in the current document for removed lines
+	Index int
+
+	// Current is nil for added lines, Desired is nil for removed lines
+	Current *LineItem
+	Desired *LineItem
+
+	// Fields that differ on changed lines
+	Fields []string
+}
+
+// DocumentChanges previews what an update does to a document
+type DocumentChanges struct {
+	// Fields of the document itself that change
+	Fields    []string
+	LineItems []LineItemChange
+}
+
+// HasChanges reports whether the update changes anything
+func (c *DocumentChanges) HasChanges() bool {
+	return len(c.Fields) > 0 || len(c.LineItems) > 0
+}
+
+// String lists the changes one per line, marking added lines with +, changed lines and fields with ~ and removed lines with -
+func (c *DocumentChanges) String() string {
+	lines := []string{}
+	for _, field := range c.Fields {
+		lines = append(lines, "~ "+field)
+	}
+	for _, change := range c.LineItems {
+		switch change.Type {
+		case LineItemAdded:
+			lines = append(lines, fmt.Sprintf("+ LineItems[%d] %s", change.Index, change.Desired.Description))
+		case LineItemChanged:
+			lines = append(lines, fmt.Sprintf("~ LineItems[%d] %s: %s", change.Index, change.Desired.Description, strings.Join(change.Fields, ", ")))
+		case LineItemRemoved:
+			lines = append(lines, fmt.Sprintf("- LineItems[%d] %s", change.Index, change.Current.Description))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffLineItems matches the desired line items to the current ones, first by LineItemID and then by Description
+// and AccountCode. Matched lines get the LineItemID of the current line, so Xero keeps them instead of deleting
+// and recreating them. A LineItemID that is not on the current document is cleared, so the line is added.
+// It returns the lines to send and the changes they make, or ErrRemoveAllLineItems when desired has no lines
+func DiffLineItems(current []LineItem, desired []LineItem) ([]LineItem, []LineItemChange, error) {
+	if len(desired) == 0 && len(current) > 0 {
+		return nil, nil, ErrRemoveAllLineItems
+	}
+	lines := make([]LineItem, len(desired))
+	copy(lines, desired)
+	matched := make([]int, len(desired))
+	used := make([]bool, len(current))
+	for n := range matched {
+		matched[n] = -1
+	}
+
+	ids := map[string]int{}
+	for n, line := range current {
+		if line.LineItemID != "" {
+			ids[line.LineItemID] = n
+		}
+	}
+	for n, line := range lines {
+		if line.LineItemID == "" {
+			continue
+		}
+		if c, ok := ids[line.LineItemID]; ok && !used[c] {
+			matched[n] = c
+			used[c] = true
+		} else {
+			//Xero rejects an ID it does not know
+			lines[n].LineItemID = ""
+		}
+	}
+	for n, line := range lines {
+		if matched[n] >= 0 {
+			continue
+		}
+		for c := range current {
+			if !used[c] && current[c].Description == line.Description && current[c].AccountCode == line.AccountCode {
+				matched[n] = c
+				used[c] = true
+				lines[n].LineItemID = current[c].LineItemID
+				break
+			}
+		}
+	}
+
+	changes := []LineItemChange{}
+	for n := range lines {
+		if matched[n] < 0 {
+			changes = append(changes, LineItemChange{Type: LineItemAdded, Index: n, Desired: &lines[n]})
+			continue
+		}
+		if fields := lineItemFields(current[matched[n]], lines[n]); len(fields) > 0 {
+			changes = append(changes, LineItemChange{Type: LineItemChanged, Index: n, Current: &current[matched[n]], Desired: &lines[n], Fields: fields})
+		}
+	}
+	for c := range current {
+		if !used[c] {
+			changes = append(changes, LineItemChange{Type: LineItemRemoved, Index: c, Current: &current[c]})
+		}
+	}
+	return lines, changes, nil
+}
+
+// lineItemFields lists the fields that differ between two lines. Amounts Xero works out - a LineAmount next to
+// a Quantity and UnitAmount, and the TaxAmount - only count when the desired line sets them
+func lineItemFields(current LineItem, desired LineItem) []string {
+	fields := []string{}
+	text := func(field string, c string, d string) {
+		if c != d {
+			fields = append(fields, field)
+		}
+	}
+	amount := func(field string, c decimal.Decimal, d decimal.Decimal, calculated bool) {
+		if calculated && d.IsZero() {
+			return
+		}
+		if !c.Equal(d) {
+			fields = append(fields, field)
+		}
+	}
+	text("Description", current.Description, desired.Description)
+	amount("Quantity", current.Quantity, desired.Quantity, false)
+	amount("UnitAmount", current.UnitAmount, desired.UnitAmount, false)
+	text("ItemCode", current.ItemCode, desired.ItemCode)
+	text("AccountCode", current.AccountCode, desired.AccountCode)
+	if desired.TaxType != "" {
+		text("TaxType", string(current.TaxType), string(desired.TaxType))
+	}
+	amount("TaxAmount", current.TaxAmount, desired.TaxAmount, true)
+	amount("LineAmount", current.LineAmount, desired.LineAmount, !desired.Quantity.IsZero() && !desired.UnitAmount.IsZero())
+	amount("DiscountRate", current.DiscountRate, desired.DiscountRate, false)
+	if trackingKey(current.Tracking) != trackingKey(desired.Tracking) {
+		fields = append(fields, "Tracking")
+	}
+	return fields
+}
+
+func trackingKey(tracking []TrackingCategory) string {
+	options := []string{}
+	for _, t := range tracking {
+		options = append(options, t.Name+"="+t.Option)
+	}
+	return strings.Join(options, ";")
+}
+
+// documentFields collects the fields of a document that an update changes. Fields left empty in the
+// desired document keep their current value
+type documentFields struct {
+	fields []string
+}
+
+func (d *documentFields) text(field string, current string, desired string, set func(string)) {
+	if desired != "" && desired != current {
+		d.fields = append(d.fields, field)
+		set(desired)
+	}
+}
+
+// date compares the date part only, as Xero returns dates with a time
+func (d *documentFields) date(field string, current xerogolang.Date, desired xerogolang.Date, set func(xerogolang.Date)) {
+	if !desired.IsZero() && !desired.Equal(current.Time) {
+		d.fields = append(d.fields, field)
+		set(desired)
+	}
+}
+
+func (d *documentFields) amount(field string, current decimal.Decimal, desired decimal.Decimal, set func(decimal.Decimal)) {
+	if !desired.IsZero() && !desired.Equal(current) {
+		d.fields = append(d.fields, field)
+		set(desired)
+	}
+}
+
+// InvoiceUpdate is the update that brings an invoice in Xero to a desired state
+type InvoiceUpdate struct {
+	Current Invoice
+
+	// Invoice is the payload - the changed fields and every line, matched lines carrying their LineItemID
+	Invoice Invoice
+	Changes DocumentChanges
+}
+
+// PlanInvoiceUpdate works out the update from the current invoice read from Xero to the desired one.
+// Fields left empty in desired keep their current value, lines missing from desired are removed
+func PlanInvoiceUpdate(current Invoice, desired Invoice) (*InvoiceUpdate, error) {
+	u := &InvoiceUpdate{
+		Current: current,
+		Invoice: Invoice{InvoiceID: current.InvoiceID, Type: current.Type, Contact: Contact{ContactID: current.Contact.ContactID}},
+	}
+	d := &documentFields{fields: []string{}}
+	d.text("Contact", current.Contact.ContactID, desired.Contact.ContactID, func(v string) { u.Invoice.Contact.ContactID = v })
+	d.date("Date", current.Date, desired.Date, func(v xerogolang.Date) { u.Invoice.Date = v })
+	d.date("DueDate", current.DueDate, desired.DueDate, func(v xerogolang.Date) { u.Invoice.DueDate = v })
+	d.text("LineAmountTypes", string(current.LineAmountTypes), string(desired.LineAmountTypes), func(v string) { u.Invoice.LineAmountTypes = LineAmountType(v) })
+	d.text("InvoiceNumber", current.InvoiceNumber, desired.InvoiceNumber, func(v string) { u.Invoice.InvoiceNumber = v })
+	d.text("Reference", current.Reference, desired.Reference, func(v string) { u.Invoice.Reference = v })
+	d.text("BrandingThemeID", current.BrandingThemeID, desired.BrandingThemeID, func(v string) { u.Invoice.BrandingThemeID = v })
+	d.text("Url", current.URL, desired.URL, func(v string) { u.Invoice.URL = v })
+	d.text("CurrencyCode", current.CurrencyCode, desired.CurrencyCode, func(v string) { u.Invoice.CurrencyCode = v })
+	d.amount("CurrencyRate", current.CurrencyRate, desired.CurrencyRate, func(v decimal.Decimal) { u.Invoice.CurrencyRate = v })
+	d.text("Status", string(current.Status), string(desired.Status), func(v string) { u.Invoice.Status = InvoiceStatus(v) })
+	d.date("ExpectedPaymentDate", current.ExpectedPaymentDate, desired.ExpectedPaymentDate, func(v xerogolang.Date) { u.Invoice.ExpectedPaymentDate = v })
+	d.date("PlannedPaymentDate", current.PlannedPaymentDate, desired.PlannedPaymentDate, func(v xerogolang.Date) { u.Invoice.PlannedPaymentDate = v })
+
+	var err error
+	if u.Invoice.LineItems, u.Changes.LineItems, err = DiffLineItems(current.LineItems, desired.LineItems); err != nil {
+		return nil, err
+	}
+	u.Changes.Fields = d.fields
+	return u, nil
+}
+
+// Apply sends the update to Xero. Nothing is sent when nothing changes, the current invoice is returned instead
+func (u *InvoiceUpdate) Apply(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Invoices, error) {
+	if !u.Changes.HasChanges() {
+		return &Invoices{Invoices: []Invoice{u.Current}}, nil
+	}
+	invoices := &Invoices{Invoices: []Invoice{u.Invoice}}
+	return invoices.Update(ctx, provider, session)
+}
+
+// CreditNoteUpdate is the update that brings a credit note in Xero to a desired state
+type CreditNoteUpdate struct {
+	Current CreditNote
+
+	// CreditNote is the payload - the changed fields and every line, matched lines carrying their LineItemID
+	CreditNote CreditNote
+	Changes    DocumentChanges
+}
+
+// PlanCreditNoteUpdate works out the update from the current credit note read from Xero to the desired one.
+// Fields left empty in desired keep their current value, lines missing from desired are removed
+func PlanCreditNoteUpdate(current CreditNote, desired CreditNote) (*CreditNoteUpdate, error) {
+	u := &CreditNoteUpdate{
+		Current:    current,
+		CreditNote: CreditNote{CreditNoteID: current.CreditNoteID, Type: current.Type, Contact: Contact{ContactID: current.Contact.ContactID}},
+	}
+	d := &documentFields{fields: []string{}}
+	d.text("Contact", current.Contact.ContactID, desired.Contact.ContactID, func(v string) { u.CreditNote.Contact.ContactID = v })
+	d.date("Date", current.Date, desired.Date, func(v xerogolang.Date) { u.CreditNote.Date = v })
+	d.text("LineAmountTypes", string(current.LineAmountTypes), string(desired.LineAmountTypes), func(v string) { u.CreditNote.LineAmountTypes = LineAmountType(v) })
+	d.text("CreditNoteNumber", current.CreditNoteNumber, desired.CreditNoteNumber, func(v string) { u.CreditNote.CreditNoteNumber = v })
+	d.text("Reference", current.Reference, desired.Reference, func(v string) { u.CreditNote.Reference = v })
+	d.text("BrandingThemeID", current.BrandingThemeID, desired.BrandingThemeID, func(v string) { u.CreditNote.BrandingThemeID = v })
+	d.text("CurrencyCode", current.CurrencyCode, desired.CurrencyCode, func(v string) { u.CreditNote.CurrencyCode = v })
+	d.amount("CurrencyRate", current.CurrencyRate, desired.CurrencyRate, func(v decimal.Decimal) { u.CreditNote.CurrencyRate = v })
+	d.text("Status", string(current.Status), string(desired.Status), func(v string) { u.CreditNote.Status = CreditNoteStatus(v) })
+
+	var err error
+	if u.CreditNote.LineItems, u.Changes.LineItems, err = DiffLineItems(current.LineItems, desired.LineItems); err != nil {
+		return nil, err
+	}
+	u.Changes.Fields = d.fields
+	return u, nil
+}
+
+// Apply sends the update to Xero. Nothing is sent when nothing changes, the current credit note is returned instead
+func (u *CreditNoteUpdate) Apply(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*CreditNotes, error) {
+	if !u.Changes.HasChanges() {
+		return &CreditNotes{CreditNotes: []CreditNote{u.Current}}, nil
+	}
+	creditNotes := &CreditNotes{CreditNotes: []CreditNote{u.CreditNote}}
+	return creditNotes.Update(ctx, provider, session)
+}
@@ -0,0 +1,109 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DiffLineItems(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	current := []LineItem{
+		{LineItemID: "line-1", Description: "Consulting", AccountCode: "200", Quantity: decimal.NewFromInt(2), UnitAmount: decimal.NewFromInt(50), LineAmount: decimal.NewFromInt(100)},
+		{LineItemID: "line-2", Description: "Setup fee", AccountCode: "200", LineAmount: decimal.NewFromInt(25)},
+		{LineItemID: "line-3", Description: "Travel", AccountCode: "420", LineAmount: decimal.NewFromInt(40), Tracking: []TrackingCategory{{Name: "Region", Option: "North"}}},
+	}
+	desired := []LineItem{
+		{Description: "Travel", AccountCode: "420", LineAmount: decimal.NewFromInt(40), Tracking: []TrackingCategory{{Name: "Region", Option: "North"}}},
+		{LineItemID: "line-1", Description: "Consulting", AccountCode: "200", Quantity: decimal.NewFromInt(3), UnitAmount: decimal.NewFromInt(50)},
+		{Description: "Freight", AccountCode: "200", LineAmount: decimal.NewFromInt(15)},
+	}
+
+	lines, changes, err := DiffLineItems(current, desired)
+	a.NoError(err)
+	a.Equal("line-3", lines[0].LineItemID)
+	a.Equal("line-1", lines[1].LineItemID)
+	a.Equal("", lines[2].LineItemID)
+	a.Len(changes, 3)
+	a.Equal(LineItemChanged, changes[0].Type)
+	a.Equal([]string{"Quantity"}, changes[0].Fields)
+	a.Equal(LineItemAdded, changes[1].Type)
+	a.Equal(2, changes[1].Index)
+	a.Equal(LineItemRemoved, changes[2].Type)
+	a.Equal("Setup fee", changes[2].Current.Description)
+
+	//an ID that is not on the document is not sent
+	lines, changes, err = DiffLineItems(current, []LineItem{{LineItemID: "line-9", Description: "Freight", AccountCode: "200"}})
+	a.NoError(err)
+	a.Equal("", lines[0].LineItemID)
+	a.Equal(LineItemAdded, changes[0].Type)
+	a.Equal("", changes[0].Desired.LineItemID)
+
+	_, _, err = DiffLineItems(current, nil)
+	a.Equal(ErrRemoveAllLineItems, err)
+	lines, changes, err = DiffLineItems(nil, nil)
+	a.NoError(err)
+	a.Empty(lines)
+	a.Empty(changes)
+}
+
+func Test_PlanInvoiceUpdate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	current := Invoice{
+		InvoiceID: "inv-1",
+		Type:      "ACCREC",
+		Contact:   Contact{ContactID: "contact-1", Name: "City Limousines"},
+		Date:      xerogolang.NewDate(2024, time.March, 1),
+		Reference: "PO-7",
+		Status:    "DRAFT",
+		LineItems: []LineItem{
+			{LineItemID: "line-1", Description: "Consulting", AccountCode: "200", LineAmount: decimal.NewFromInt(100)},
+			{LineItemID: "line-2", Description: "Setup fee", AccountCode: "200", LineAmount: decimal.NewFromInt(25)},
+		},
+	}
+
+	//the same document gives no update
+	unchanged, err := PlanInvoiceUpdate(current, Invoice{Date: xerogolang.NewDate(2024, time.March, 1), LineItems: []LineItem{
+		{Description: "Consulting", AccountCode: "200", LineAmount: decimal.NewFromInt(100)},
+		{Description: "Setup fee", AccountCode: "200", LineAmount: decimal.NewFromInt(25)},
+	}})
+	a.NoError(err)
+	a.False(unchanged.Changes.HasChanges())
+	provider := &testProvider{responses: map[string]string{}}
+	invoices, err := unchanged.Apply(context.Background(), provider, nil)
+	a.NoError(err)
+	a.Equal("inv-1", invoices.Invoices[0].InvoiceID)
+	a.Empty(provider.requests)
+
+	update, err := PlanInvoiceUpdate(current, Invoice{Reference: "PO-8", LineItems: []LineItem{
+		{Description: "Consulting", AccountCode: "200", LineAmount: decimal.NewFromInt(120)},
+	}})
+	a.NoError(err)
+	a.Equal([]string{"Reference"}, update.Changes.Fields)
+	a.Equal("~ Reference\n~ LineItems[0] Consulting: LineAmount\n- LineItems[1] Setup fee", update.Changes.String())
+
+	provider.responses["POST Invoices/inv-1"] = `{"Invoices":[{"InvoiceID":"inv-1"}]}`
+	_, err = update.Apply(context.Background(), provider, nil)
+	a.NoError(err)
+	a.Len(provider.requests, 1)
+	sent := &Invoices{}
+	a.NoError(json.Unmarshal([]byte(provider.requests[0].Body), sent))
+	a.Equal("PO-8", sent.Invoices[0].Reference)
+	a.Equal("contact-1", sent.Invoices[0].Contact.ContactID)
+	a.True(sent.Invoices[0].Date.IsZero())
+	a.Len(sent.Invoices[0].LineItems, 1)
+	a.Equal("line-1", sent.Invoices[0].LineItems[0].LineItemID)
+
+	//removing every line is refused rather than previewed
+	_, err = PlanInvoiceUpdate(current, Invoice{Reference: "PO-9"})
+	a.Equal(ErrRemoveAllLineItems, err)
+}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // LinkedTransaction can link transactions from an Accounts Payable invoice to an
@@ -40,7 +39,7 @@ type LinkedTransaction struct {
 	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// The last modified date in UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// The Type of the source tranasction. This will be ACCPAY if the linked transaction was created from an invoice and SPEND if it was created from a bank transaction.
 	SourceTransactionTypeCode string `json:"SourceTransactionTypeCode,omitempty" xml:"SourceTransactionTypeCode,omitempty"`
@@ -51,20 +50,6 @@ type LinkedTransactions struct {
 	LinkedTransactions []LinkedTransaction `json:"LinkedTransactions" xml:"LinkedTransaction"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (l *LinkedTransactions) convertDates() error {
-	var err error
-	for n := len(l.LinkedTransactions) - 1; n >= 0; n-- {
-		l.LinkedTransactions[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(l.LinkedTransactions[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalLinkedTransaction(linkedTransactionResponseBytes []byte) (*LinkedTransactions, error) {
 	var linkedTransactionResponse *LinkedTransactions
 	err := json.Unmarshal(linkedTransactionResponseBytes, &linkedTransactionResponse)
@@ -72,11 +57,6 @@ func unmarshalLinkedTransaction(linkedTransactionResponseBytes []byte) (*LinkedT
 		return nil, err
 	}
 
-	err = linkedTransactionResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return linkedTransactionResponse, err
 }
 
@@ -0,0 +1,128 @@
+package accounting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omniboost/xerogolang/query"
+)
+
+// MaxFilterValues is the most values sent in a single IDs, InvoiceNumbers or ContactIDs filter.
+// Longer lists are split across several requests to keep the URL within the limits of the API
+const MaxFilterValues = 40
+
+var (
+	// ErrSummaryOnlyWithPage is returned when summaryOnly is combined with a page - summaries are never paged
+	ErrSummaryOnlyWithPage = errors.New("summaryOnly cannot be combined with page")
+
+	// ErrPageWithSplitFilter is returned when a page is requested for a filter that has to be split across several requests
+	ErrPageWithSplitFilter = fmt.Errorf("page cannot be combined with filters of more than %d values", MaxFilterValues)
+
+	guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// ListOptions are the options shared by the Find...WithOptions functions
+type ListOptions struct {
+	// Only records modified after this time are returned when set
+	ModifiedSince time.Time
+
+	// where clause - see the query package
+	Where query.Condition
+
+	// order clause - see the query package
+	Order []query.Order
+
+	// Page of 100 records to return. Paged records include details like line items
+	Page int
+}
+
+func (l ListOptions) querystringParameters() map[string]string {
+	return query.New().Where(l.Where).OrderBy(l.Order...).Page(l.Page).MergeParams(nil)
+}
+
+// listFilter is a filter that takes a comma separated list of values e.g. IDs
+type listFilter struct {
+	name   string
+	values []string
+}
+
+// splitListFilters adds filters to params and returns one set of querystringParameters for each request
+// needed to send all values. Xero joins filters with AND, so when more than one filter is split every
+// combination of the chunks is requested - the chunks don't overlap so no record is returned twice
+func splitListFilters(page int, params map[string]string, filters ...listFilter) ([]map[string]string, error) {
+	paramSets := []map[string]string{params}
+	for _, filter := range filters {
+		if len(filter.values) == 0 {
+			continue
+		}
+		chunks := [][]string{}
+		for start := 0; start < len(filter.values); start += MaxFilterValues {
+			end := start + MaxFilterValues
+			if end > len(filter.values) {
+				end = len(filter.values)
+			}
+			chunks = append(chunks, filter.values[start:end])
+		}
+		if len(chunks) > 1 && page > 0 {
+			return nil, ErrPageWithSplitFilter
+		}
+
+		split := []map[string]string{}
+		for _, existing := range paramSets {
+			for _, chunk := range chunks {
+				p := map[string]string{}
+				for key, value := range existing {
+					p[key] = value
+				}
+				p[filter.name] = strings.Join(chunk, ",")
+				split = append(split, p)
+			}
+		}
+		paramSets = split
+	}
+	return paramSets, nil
+}
+
+func validateIDs(name string, ids []string) error {
+	for _, id := range ids {
+		if !guidPattern.MatchString(id) {
+			return fmt.Errorf("%s contains %q which is not a valid Xero identifier", name, id)
+		}
+	}
+	return nil
+}
+
+func validateValues(name string, values []string) error {
+	for _, value := range values {
+		if value == "" {
+			return fmt.Errorf("%s cannot contain an empty value", name)
+		}
+		if strings.Contains(value, ",") {
+			return fmt.Errorf("%s contains %q - values cannot contain a comma", name, value)
+		}
+	}
+	return nil
+}
+
+func validateUnitDP(unitDP int) error {
+	if unitDP != 0 && unitDP != 2 && unitDP != 4 {
+		return fmt.Errorf("unitdp must be 2 or 4, not %d", unitDP)
+	}
+	return nil
+}
+
+func addUnitDP(params map[string]string, unitDP int) {
+	if unitDP != 0 {
+		params["unitdp"] = strconv.Itoa(unitDP)
+	}
+}
+
+func addFlag(params map[string]string, name string, set bool) {
+	if set {
+		params[name] = "true"
+	}
+}
@@ -0,0 +1,66 @@
+package accounting
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testIDs(count int) []string {
+	ids := make([]string, count)
+	for n := range ids {
+		ids[n] = fmt.Sprintf("00000000-0000-0000-0000-%012d", n)
+	}
+	return ids
+}
+
+func Test_InvoicesOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	options := &InvoicesOptions{
+		Statuses:    []string{"DRAFT", "AUTHORISED"},
+		SummaryOnly: true,
+		UnitDP:      4,
+	}
+	paramSets, err := options.querystringParameters()
+	a.NoError(err)
+	a.Equal([]map[string]string{{
+		"Statuses":    "DRAFT,AUTHORISED",
+		"summaryOnly": "true",
+		"unitdp":      "4",
+	}}, paramSets)
+
+	options = &InvoicesOptions{SummaryOnly: true, ListOptions: ListOptions{Page: 1}}
+	_, err = options.querystringParameters()
+	a.Equal(ErrSummaryOnlyWithPage, err)
+
+	options = &InvoicesOptions{IDs: []string{"INV-001"}}
+	_, err = options.querystringParameters()
+	a.Error(err)
+}
+
+func Test_SplitListFilters(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	options := &InvoicesOptions{
+		IDs:        testIDs(MaxFilterValues*2 + 1),
+		ContactIDs: testIDs(MaxFilterValues + 1),
+	}
+	paramSets, err := options.querystringParameters()
+	a.NoError(err)
+	a.Len(paramSets, 6)
+
+	seen := map[string]bool{}
+	for _, params := range paramSets {
+		key := params["IDs"] + "|" + params["ContactIDs"]
+		a.False(seen[key])
+		seen[key] = true
+	}
+
+	options.Page = 2
+	_, err = options.querystringParameters()
+	a.Equal(ErrPageWithSplitFilter, err)
+}
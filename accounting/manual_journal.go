@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -22,13 +21,13 @@ type ManualJournal struct {
 	JournalLines []ManualJournalLine `json:"JournalLines" xml:"JournalLines>JournalLine"`
 
 	// Date journal was posted – YYYY-MM-DD
-	Date string `json:"Date,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"Date,omitzero" xml:"Date,omitempty"`
 
 	// NoTax by default if you don’t specify this element. See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// See Manual Journal Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status ManualJournalStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Url link to a source document – shown as “Go to [appName]” in the Xero app
 	URL string `json:"Url,omitempty" xml:"Url,omitempty"`
@@ -40,7 +39,7 @@ type ManualJournal struct {
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"-"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// The Xero identifier for a Manual Journal
 	ManualJournalID string `json:"ManualJournalID,omitempty" xml:"ManualJournalID,omitempty"`
@@ -53,24 +52,6 @@ type ManualJournals struct {
 	ManualJournals []ManualJournal `json:"ManualJournals,omitempty" xml:"ManualJournal,omitempty"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (m *ManualJournals) convertDates() error {
-	var err error
-	for n := len(m.ManualJournals) - 1; n >= 0; n-- {
-		m.ManualJournals[n].Date, err = helpers.DotNetJSONTimeToRFC3339(m.ManualJournals[n].Date, false)
-		if err != nil {
-			return err
-		}
-		m.ManualJournals[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(m.ManualJournals[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalManualJournal(manualJournalResponseBytes []byte) (*ManualJournals, error) {
 	var manualJournalResponse *ManualJournals
 	err := json.Unmarshal(manualJournalResponseBytes, &manualJournalResponse)
@@ -78,16 +59,16 @@ func unmarshalManualJournal(manualJournalResponseBytes []byte) (*ManualJournals,
 		return nil, err
 	}
 
-	err = manualJournalResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return manualJournalResponse, err
 }
 
 // Create will create manualJournals given an ManualJournals struct
+// Journals whose debits and credits differ are refused before they are sent
 func (m *ManualJournals) Create(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ManualJournals, error) {
+	if err := m.checkBalance(); err != nil {
+		return nil, err
+	}
+
 	additionalHeaders := map[string]string{
 		"Accept":       "application/json",
 		"Content-Type": "application/json",
@@ -185,7 +166,7 @@ func GenerateExampleManualJournal() *ManualJournals {
 
 	manualJournal := ManualJournal{
 		Narration:       "Missed Importing & Exporting Invoice",
-		Date:            helpers.TodayRFC3339(),
+		Date:            xerogolang.Today(),
 		LineAmountTypes: "Exclusive",
 		Status:          "DRAFT",
 		JournalLines:    []ManualJournalLine{},
@@ -0,0 +1,185 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+)
+
+// ErrJournalTaxUnknown is returned when a tax exclusive journal has a line with a TaxType
+// but no TaxAmount and there is no TaxAmountFunc to work it out
+var ErrJournalTaxUnknown = errors.New("the tax of a journal line is unknown - set its TaxAmount or a TaxAmountFunc")
+
+// TaxAmountFunc works out the tax of a manual journal line, e.g. tax.Calculator.JournalLineTax
+type TaxAmountFunc func(line ManualJournalLine, inclusive bool) (decimal.Decimal, error)
+
+// Imbalance returns the total debits less the total credits of the journal. Lines of a tax exclusive journal
+// post their tax on top of the line amount, so the tax is taken into account. Lines without a TaxAmount have
+// their tax worked out by taxAmount - when it is nil only lines with a TaxAmount or the NONE tax type can be
+// balanced, as Xero applies the tax of the account to lines without a TaxType
+func (m *ManualJournal) Imbalance(taxAmount TaxAmountFunc) (decimal.Decimal, error) {
+	total := decimal.Zero
+	for n, line := range m.JournalLines {
+		total = total.Add(line.LineAmount)
+		if m.LineAmountTypes != LineAmountTypeExclusive {
+			continue
+		}
+		tax := line.TaxAmount
+		if tax.IsZero() && taxAmount != nil {
+			var err error
+			tax, err = taxAmount(line, false)
+			if err != nil {
+				return decimal.Zero, fmt.Errorf("JournalLines[%d]: %s", n, err.Error())
+			}
+		} else if tax.IsZero() && line.TaxType != TaxTypeNone {
+			return decimal.Zero, ErrJournalTaxUnknown
+		}
+		total = total.Add(tax)
+	}
+	return total, nil
+}
+
+// checkBalance returns an error for the first journal whose debits and credits differ.
+// Journals whose tax cannot be worked out are left to Xero
+func (m *ManualJournals) checkBalance() error {
+	for n := range m.ManualJournals {
+		imbalance, err := m.ManualJournals[n].Imbalance(nil)
+		if err == ErrJournalTaxUnknown {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !imbalance.IsZero() {
+			return fmt.Errorf("ManualJournals[%d]: debits and credits differ by %s", n, imbalance.StringFixed(2))
+		}
+	}
+	return nil
+}
+
+// ManualJournalBuilder puts together a manual journal line by line and checks it balances before it is created
+type ManualJournalBuilder struct {
+	journal             ManualJournal
+	suspenseAccountCode string
+	taxAmount           TaxAmountFunc
+	err                 error
+}
+
+// NewManualJournalBuilder starts a manual journal. Journals have no tax unless LineAmountTypes is set
+func NewManualJournalBuilder(narration string, date time.Time) *ManualJournalBuilder {
+	return &ManualJournalBuilder{
+		journal: ManualJournal{
+			Narration:       narration,
+			Date:            xerogolang.DateOf(date),
+			LineAmountTypes: LineAmountTypeNoTax,
+			JournalLines:    []ManualJournalLine{},
+		},
+	}
+}
+
+// LineAmountTypes sets whether the line amounts are Exclusive, Inclusive or NoTax
+func (b *ManualJournalBuilder) LineAmountTypes(lineAmountTypes LineAmountType) *ManualJournalBuilder {
+	b.journal.LineAmountTypes = lineAmountTypes
+	return b
+}
+
+// Status sets the status the journal is created with - DRAFT or POSTED
+func (b *ManualJournalBuilder) Status(status ManualJournalStatus) *ManualJournalBuilder {
+	b.journal.Status = status
+	return b
+}
+
+// TaxCalculator sets how the tax of lines without a TaxAmount is worked out for a tax exclusive journal
+func (b *ManualJournalBuilder) TaxCalculator(taxAmount TaxAmountFunc) *ManualJournalBuilder {
+	b.taxAmount = taxAmount
+	return b
+}
+
+// BalanceTo posts any difference between debits and credits to a suspense account instead of failing
+func (b *ManualJournalBuilder) BalanceTo(suspenseAccountCode string) *ManualJournalBuilder {
+	b.suspenseAccountCode = suspenseAccountCode
+	return b
+}
+
+// Debit adds a line debiting amount to line.AccountCode. The Description, TaxType, TaxAmount and Tracking of line are kept
+func (b *ManualJournalBuilder) Debit(line ManualJournalLine, amount decimal.Decimal) *ManualJournalBuilder {
+	return b.add(line, amount, false)
+}
+
+// Credit adds a line crediting amount to line.AccountCode. The Description, TaxType, TaxAmount and Tracking of line are kept
+func (b *ManualJournalBuilder) Credit(line ManualJournalLine, amount decimal.Decimal) *ManualJournalBuilder {
+	return b.add(line, amount, true)
+}
+
+func (b *ManualJournalBuilder) add(line ManualJournalLine, amount decimal.Decimal, credit bool) *ManualJournalBuilder {
+	if amount.IsNegative() {
+		if b.err == nil {
+			b.err = fmt.Errorf("JournalLines[%d]: amount %s must not be negative", len(b.journal.JournalLines), amount.String())
+		}
+		return b
+	}
+	line.LineAmount = amount
+	if credit {
+		line.LineAmount = amount.Neg()
+		line.TaxAmount = line.TaxAmount.Abs().Neg()
+	} else {
+		line.TaxAmount = line.TaxAmount.Abs()
+	}
+	b.journal.JournalLines = append(b.journal.JournalLines, line)
+	return b
+}
+
+// Imbalance returns the total debits less the total credits of the lines added so far
+func (b *ManualJournalBuilder) Imbalance() (decimal.Decimal, error) {
+	return b.journal.Imbalance(b.taxAmount)
+}
+
+// Build returns the journal ready to be created. A journal that does not balance is an error
+// unless a suspense account was set, which then gets a line for the difference
+func (b *ManualJournalBuilder) Build() (*ManualJournals, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	journal := b.journal
+	journal.JournalLines = append([]ManualJournalLine{}, b.journal.JournalLines...)
+
+	imbalance, err := journal.Imbalance(b.taxAmount)
+	if err != nil {
+		return nil, err
+	}
+	if !imbalance.IsZero() {
+		if b.suspenseAccountCode == "" {
+			return nil, fmt.Errorf("debits and credits differ by %s", imbalance.StringFixed(2))
+		}
+		line := ManualJournalLine{
+			AccountCode: b.suspenseAccountCode,
+			Description: "Balancing line",
+			LineAmount:  imbalance.Neg(),
+		}
+		//the suspense line must not pick up the default tax of its account
+		if journal.LineAmountTypes.HasTax() {
+			line.TaxType = "NONE"
+		}
+		journal.JournalLines = append(journal.JournalLines, line)
+	}
+
+	manualJournals := &ManualJournals{ManualJournals: []ManualJournal{journal}}
+	if err := manualJournals.Validate(); err != nil {
+		return nil, err
+	}
+	return manualJournals, nil
+}
+
+// Create builds the journal and creates it in Xero
+func (b *ManualJournalBuilder) Create(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ManualJournals, error) {
+	manualJournals, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return manualJournals.Create(ctx, provider, session)
+}
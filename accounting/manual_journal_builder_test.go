@@ -0,0 +1,102 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ManualJournalBuilder(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	date := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	tracking := []TrackingCategory{{Name: "Region", Option: "North"}}
+	journals, err := NewManualJournalBuilder("Accrued rent", date).
+		Debit(ManualJournalLine{AccountCode: "469", Description: "Rent March", Tracking: tracking}, decimal.NewFromInt(1200)).
+		Credit(ManualJournalLine{AccountCode: "825", Description: "Rent March"}, decimal.NewFromInt(1200)).
+		Build()
+	a.NoError(err)
+	a.Len(journals.ManualJournals, 1)
+	journal := journals.ManualJournals[0]
+	a.Equal("2024-03-31", journal.Date.String())
+	a.Equal(LineAmountTypeNoTax, journal.LineAmountTypes)
+	a.Equal("1200", journal.JournalLines[0].LineAmount.String())
+	a.Equal("-1200", journal.JournalLines[1].LineAmount.String())
+	a.Equal(tracking, journal.JournalLines[0].Tracking)
+
+	_, err = NewManualJournalBuilder("Unbalanced", date).
+		Debit(ManualJournalLine{AccountCode: "469"}, decimal.NewFromInt(100)).
+		Credit(ManualJournalLine{AccountCode: "825"}, decimal.NewFromInt(90)).
+		Build()
+	a.EqualError(err, "debits and credits differ by 10.00")
+
+	_, err = NewManualJournalBuilder("Negative", date).
+		Debit(ManualJournalLine{AccountCode: "469"}, decimal.NewFromInt(-100)).
+		Build()
+	a.Error(err)
+}
+
+func Test_ManualJournalBuilderBalanceTo(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	//tax exclusive lines post their tax on top of the line amount
+	builder := NewManualJournalBuilder("Expense reclass", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)).
+		LineAmountTypes("Exclusive").
+		Debit(ManualJournalLine{AccountCode: "429", TaxType: "INPUT2"}, decimal.NewFromInt(100)).
+		Credit(ManualJournalLine{AccountCode: "800", TaxType: "NONE"}, decimal.NewFromInt(100)).
+		BalanceTo("9999")
+
+	_, err := builder.Imbalance()
+	a.Equal(ErrJournalTaxUnknown, err)
+
+	builder.TaxCalculator(func(line ManualJournalLine, inclusive bool) (decimal.Decimal, error) {
+		if line.TaxType == "INPUT2" {
+			return line.LineAmount.Mul(decimal.RequireFromString("0.15")).Round(2), nil
+		}
+		return decimal.Zero, nil
+	})
+	imbalance, err := builder.Imbalance()
+	a.NoError(err)
+	a.Equal("15", imbalance.String())
+
+	journals, err := builder.Build()
+	a.NoError(err)
+	lines := journals.ManualJournals[0].JournalLines
+	a.Len(lines, 3)
+	a.Equal("9999", lines[2].AccountCode)
+	a.Equal(TaxTypeNone, lines[2].TaxType)
+	a.Equal("-15", lines[2].LineAmount.String())
+}
+
+func Test_ManualJournalsCheckBalance(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.NoError(GenerateExampleManualJournal().checkBalance())
+
+	journals := &ManualJournals{ManualJournals: []ManualJournal{{
+		Narration:       "Exclusive with explicit tax",
+		LineAmountTypes: "Exclusive",
+		JournalLines: []ManualJournalLine{
+			{AccountCode: "429", TaxType: "INPUT2", LineAmount: decimal.NewFromInt(100), TaxAmount: decimal.NewFromInt(15)},
+			{AccountCode: "800", TaxType: "NONE", LineAmount: decimal.NewFromInt(-100)},
+		},
+	}}}
+	a.EqualError(journals.checkBalance(), "ManualJournals[0]: debits and credits differ by 15.00")
+
+	//Xero applies the tax of the account to lines without a TaxType, so the balance is left to Xero
+	journals = &ManualJournals{ManualJournals: []ManualJournal{{
+		Narration:       "Exclusive with account tax",
+		LineAmountTypes: "Exclusive",
+		JournalLines: []ManualJournalLine{
+			{AccountCode: "429", LineAmount: decimal.NewFromInt(100)},
+			{AccountCode: "800", TaxType: "NONE", LineAmount: decimal.NewFromInt(-115)},
+		},
+	}}}
+	a.NoError(journals.checkBalance())
+	a.NoError(journals.Validate())
+}
@@ -0,0 +1,51 @@
+package accounting
+
+import (
+	"fmt"
+
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+)
+
+// baseMoney converts an amount of a document to the base currency of the organisation. Documents without
+// a CurrencyCode are in the base currency
+func baseMoney(resource string, id string, currencyCode string, currencyRate decimal.Decimal, amount decimal.Decimal, baseCurrency string) (xerogolang.Money, error) {
+	if currencyCode == "" {
+		return xerogolang.NewMoney(amount, baseCurrency), nil
+	}
+	money, err := xerogolang.NewMoney(amount, currencyCode).Convert(baseCurrency, currencyRate)
+	if err != nil {
+		return xerogolang.Money{}, fmt.Errorf("%s/%s: %s", resource, id, err.Error())
+	}
+	return money, nil
+}
+
+// Money returns an amount of the invoice, such as its Total or AmountDue, in the currency of the invoice
+func (i *Invoice) Money(amount decimal.Decimal) xerogolang.Money {
+	return xerogolang.NewMoney(amount, i.CurrencyCode)
+}
+
+// BaseMoney converts an amount of the invoice to the base currency of the organisation using the CurrencyRate of the invoice
+func (i *Invoice) BaseMoney(amount decimal.Decimal, baseCurrency string) (xerogolang.Money, error) {
+	return baseMoney("Invoices", i.InvoiceID, i.CurrencyCode, i.CurrencyRate, amount, baseCurrency)
+}
+
+// Money returns an amount of the credit note, such as its RemainingCredit, in the currency of the credit note
+func (c *CreditNote) Money(amount decimal.Decimal) xerogolang.Money {
+	return xerogolang.NewMoney(amount, c.CurrencyCode)
+}
+
+// BaseMoney converts an amount of the credit note to the base currency of the organisation using the CurrencyRate of the credit note
+func (c *CreditNote) BaseMoney(amount decimal.Decimal, baseCurrency string) (xerogolang.Money, error) {
+	return baseMoney("CreditNotes", c.CreditNoteID, c.CurrencyCode, c.CurrencyRate, amount, baseCurrency)
+}
+
+// Money returns an amount of the bank transaction, such as its Total, in the currency of the bank transaction
+func (b *BankTransaction) Money(amount decimal.Decimal) xerogolang.Money {
+	return xerogolang.NewMoney(amount, b.CurrencyCode)
+}
+
+// BaseMoney converts an amount of the bank transaction to the base currency of the organisation using the CurrencyRate of the bank transaction
+func (b *BankTransaction) BaseMoney(amount decimal.Decimal, baseCurrency string) (xerogolang.Money, error) {
+	return baseMoney("BankTransactions", b.BankTransactionID, b.CurrencyCode, b.CurrencyRate, amount, baseCurrency)
+}
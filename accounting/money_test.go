@@ -0,0 +1,28 @@
+package accounting
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BaseMoney(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	invoice := Invoice{InvoiceID: "inv-1", CurrencyCode: "USD", CurrencyRate: decimal.RequireFromString("0.6"), AmountDue: decimal.NewFromInt(50)}
+	a.Equal("USD 50.00", invoice.Money(invoice.AmountDue).String())
+	base, err := invoice.BaseMoney(invoice.AmountDue, "NZD")
+	a.NoError(err)
+	a.Equal("NZD 83.33", base.String())
+
+	creditNote := CreditNote{CreditNoteID: "cn-1", CurrencyCode: "JPY", RemainingCredit: decimal.NewFromInt(1000)}
+	_, err = creditNote.BaseMoney(creditNote.RemainingCredit, "NZD")
+	a.EqualError(err, "CreditNotes/cn-1: cannot convert JPY to NZD at a rate of 0")
+
+	bankTransaction := BankTransaction{Total: decimal.RequireFromString("12.34")}
+	base, err = bankTransaction.BaseMoney(bankTransaction.Total, "NZD")
+	a.NoError(err)
+	a.Equal("NZD 12.34", base.String())
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // Organisation is information about a Xero organisation
@@ -67,13 +66,13 @@ type Organisation struct {
 	DefaultPurchasesTax string `json:"DefaultPurchasesTax,omitempty"`
 
 	// Shown if set. See lock dates
-	PeriodLockDate string `json:"PeriodLockDate,omitempty"`
+	PeriodLockDate xerogolang.Date `json:"PeriodLockDate,omitzero"`
 
 	// Shown if set. See lock dates
-	EndOfYearLockDate string `json:"EndOfYearLockDate,omitempty"`
+	EndOfYearLockDate xerogolang.Date `json:"EndOfYearLockDate,omitzero"`
 
 	// Timestamp when the organisation was created in Xero
-	CreatedDateUTC string `json:"CreatedDateUTC,omitempty"`
+	CreatedDateUTC xerogolang.DateTime `json:"CreatedDateUTC,omitzero"`
 
 	// Timezone specifications
 	Timezone string `json:"Timezone,omitempty"`
@@ -122,14 +121,5 @@ func FindOrganisation(ctx context.Context, provider xerogolang.IProvider, sessio
 		return nil, err
 	}
 
-	organisationResponse.Organisations[0].PeriodLockDate, err = helpers.DotNetJSONTimeToRFC3339(organisationResponse.Organisations[0].PeriodLockDate, false)
-	if err != nil {
-		return nil, err
-	}
-	organisationResponse.Organisations[0].CreatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(organisationResponse.Organisations[0].CreatedDateUTC, true)
-	if err != nil {
-		return nil, err
-	}
-
 	return organisationResponse, nil
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -16,10 +15,10 @@ import (
 type Overpayment struct {
 
 	// See Overpayment Types
-	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
+	Type BankTransactionType `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// The date the overpayment is created YYYY-MM-DD
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -28,7 +27,7 @@ type Overpayment struct {
 	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// See Overpayment Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// See Overpayment Line Items
 	LineItems []LineItem `json:"LineItems,omitempty" xml:"LineItems,omitempty"`
@@ -43,7 +42,7 @@ type Overpayment struct {
 	Total decimal.Decimal `json:"Total,omitempty" xml:"Total,omitempty"`
 
 	// UTC timestamp of last update to the overpayment
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"UpdatedDateUTC,omitempty"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"UpdatedDateUTC,omitempty"`
 
 	// Currency used for the overpayment
 	CurrencyCode string `json:"CurrencyCode,omitempty" xml:"CurrencyCode,omitempty"`
@@ -72,20 +71,6 @@ type Overpayments struct {
 	Overpayments []Overpayment `json:"Overpayments" xml:"Overpayment"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (o *Overpayments) convertDates() error {
-	var err error
-	for n := len(o.Overpayments) - 1; n >= 0; n-- {
-		o.Overpayments[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(o.Overpayments[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalOverpayment(overpaymentResponseBytes []byte) (*Overpayments, error) {
 	var overpaymentResponse *Overpayments
 	err := json.Unmarshal(overpaymentResponseBytes, &overpaymentResponse)
@@ -93,11 +78,6 @@ func unmarshalOverpayment(overpaymentResponseBytes []byte) (*Overpayments, error
 		return nil, err
 	}
 
-	err = overpaymentResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return overpaymentResponse, err
 }
 
@@ -129,6 +109,39 @@ func FindOverpayments(ctx context.Context, provider xerogolang.IProvider, sessio
 	return FindOverpaymentsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// OverpaymentsOptions are the typed filters accepted by FindOverpaymentsWithOptions
+type OverpaymentsOptions struct {
+	ListOptions
+
+	// Decimal places for unit amounts - 2 or 4
+	UnitDP int
+}
+
+func (o *OverpaymentsOptions) querystringParameters() (map[string]string, error) {
+	if err := validateUnitDP(o.UnitDP); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addUnitDP(params, o.UnitDP)
+
+	return params, nil
+}
+
+// FindOverpaymentsWithOptions will get all Overpayments matching the given options
+func FindOverpaymentsWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *OverpaymentsOptions) (*Overpayments, error) {
+	if options == nil {
+		options = &OverpaymentsOptions{}
+	}
+
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindOverpaymentsModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+}
+
 // FindOverpayment will get a single overpayment - overpaymentID can be a GUID for an overpayment or an overpayment number
 func FindOverpayment(ctx context.Context, provider xerogolang.IProvider, session goth.Session, overpaymentID string) (*Overpayments, error) {
 	additionalHeaders := map[string]string{
@@ -163,3 +176,19 @@ func (o *Overpayments) Allocate(ctx context.Context, provider xerogolang.IProvid
 
 	return unmarshalOverpayment(overpaymentResponseBytes)
 }
+
+// RemoveAllocation removes a single allocation from an overpayment and returns the updated overpayment
+// allocationID must be the GUID of an allocation on the overpayment
+func (o *Overpayments) RemoveAllocation(ctx context.Context, provider xerogolang.IProvider, session goth.Session, allocationID string) (*Overpayments, error) {
+	additionalHeaders := map[string]string{
+		"Accept": "application/json",
+	}
+
+	_, err := provider.Remove(ctx, session, "Overpayments/"+o.Overpayments[0].OverpaymentID+"/Allocations/"+allocationID, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	//the delete response only contains the removed allocation so we fetch the overpayment again to get its new RemainingCredit
+	return FindOverpayment(ctx, provider, session, o.Overpayments[0].OverpaymentID)
+}
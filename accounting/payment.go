@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -25,7 +24,7 @@ type Payment struct {
 	Account *Account `json:"Account,omitempty" xml:"Account,omitempty"`
 
 	// Date the payment is being made (YYYY-MM-DD) e.g. 2009-09-06
-	Date string `json:"Date,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"Date,omitzero" xml:"Date,omitempty"`
 
 	// Exchange rate when payment is received. Only used for non base currency invoices and credit notes e.g. 0.7500
 	CurrencyRate decimal.Decimal `json:"CurrencyRate,omitempty" xml:"CurrencyRate,omitempty"`
@@ -40,13 +39,13 @@ type Payment struct {
 	IsReconciled bool `json:"IsReconciled,omitempty" xml:"IsReconciled,omitempty"`
 
 	// The status of the payment.
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status PaymentStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// See Payment Types.
-	PaymentType string `json:"PaymentType,omitempty" xml:"-"`
+	PaymentType PaymentType `json:"PaymentType,omitempty" xml:"-"`
 
 	// UTC timestamp of last update to the payment
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// The Xero identifier for an Payment e.g. 297c2dc5-cc47-4afd-8ec8-74990b8761e9
 	PaymentID string `json:"PaymentID,omitempty" xml:"PaymentID,omitempty"`
@@ -57,24 +56,6 @@ type Payments struct {
 	Payments []Payment `json:"Payments" xml:"Payment"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (p *Payments) convertDates() error {
-	var err error
-	for n := len(p.Payments) - 1; n >= 0; n-- {
-		p.Payments[n].Date, err = helpers.DotNetJSONTimeToRFC3339(p.Payments[n].Date, false)
-		if err != nil {
-			return err
-		}
-		p.Payments[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(p.Payments[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalPayment(paymentResponseBytes []byte) (*Payments, error) {
 	var paymentResponse *Payments
 	err := json.Unmarshal(paymentResponseBytes, &paymentResponse)
@@ -82,11 +63,6 @@ func unmarshalPayment(paymentResponseBytes []byte) (*Payments, error) {
 		return nil, err
 	}
 
-	err = paymentResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return paymentResponse, err
 }
 
@@ -161,6 +137,20 @@ func FindPayments(ctx context.Context, provider xerogolang.IProvider, session go
 	return FindPaymentsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// PaymentsOptions are the typed filters accepted by FindPaymentsWithOptions
+type PaymentsOptions struct {
+	ListOptions
+}
+
+// FindPaymentsWithOptions will get all payments matching the given options
+func FindPaymentsWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *PaymentsOptions) (*Payments, error) {
+	if options == nil {
+		options = &PaymentsOptions{}
+	}
+
+	return FindPaymentsModifiedSince(ctx, provider, session, options.ModifiedSince, options.ListOptions.querystringParameters())
+}
+
 // FindPayment will get a single payment - paymentID must be a GUID for an payment
 func FindPayment(ctx context.Context, provider xerogolang.IProvider, session goth.Session, paymentID string) (*Payments, error) {
 	additionalHeaders := map[string]string{
@@ -192,7 +182,7 @@ func RemovePayment(ctx context.Context, provider xerogolang.IProvider, session g
 // GenerateExamplePayment Creates an Example payment
 func GenerateExamplePayment(invoiceID string, amount decimal.Decimal) *Payments {
 	payment := Payment{
-		Date:   helpers.TodayRFC3339(),
+		Date:   xerogolang.Today(),
 		Amount: amount,
 		Invoice: &Invoice{
 			InvoiceID: invoiceID,
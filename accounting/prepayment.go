@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -16,10 +15,10 @@ import (
 type Prepayment struct {
 
 	// See Prepayment Types
-	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
+	Type BankTransactionType `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// The date the prepayment is created YYYY-MM-DD
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -28,7 +27,7 @@ type Prepayment struct {
 	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// See Prepayment Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// See Prepayment Line Items
 	LineItems []LineItem `json:"LineItems,omitempty" xml:"LineItems,omitempty"`
@@ -43,7 +42,7 @@ type Prepayment struct {
 	Total decimal.Decimal `json:"Total,omitempty" xml:"Total,omitempty"`
 
 	// UTC timestamp of last update to the prepayment
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"UpdatedDateUTC,omitempty"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"UpdatedDateUTC,omitempty"`
 
 	// Currency used for the prepayment
 	CurrencyCode string `json:"CurrencyCode,omitempty" xml:"CurrencyCode,omitempty"`
@@ -69,20 +68,6 @@ type Prepayments struct {
 	Prepayments []Prepayment `json:"Prepayments" xml:"Prepayment"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (p *Prepayments) convertDates() error {
-	var err error
-	for n := len(p.Prepayments) - 1; n >= 0; n-- {
-		p.Prepayments[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(p.Prepayments[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalPrepayment(prepaymentResponseBytes []byte) (*Prepayments, error) {
 	var prepaymentResponse *Prepayments
 	err := json.Unmarshal(prepaymentResponseBytes, &prepaymentResponse)
@@ -90,11 +75,6 @@ func unmarshalPrepayment(prepaymentResponseBytes []byte) (*Prepayments, error) {
 		return nil, err
 	}
 
-	err = prepaymentResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return prepaymentResponse, err
 }
 
@@ -126,6 +106,39 @@ func FindPrepayments(ctx context.Context, provider xerogolang.IProvider, session
 	return FindPrepaymentsModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// PrepaymentsOptions are the typed filters accepted by FindPrepaymentsWithOptions
+type PrepaymentsOptions struct {
+	ListOptions
+
+	// Decimal places for unit amounts - 2 or 4
+	UnitDP int
+}
+
+func (o *PrepaymentsOptions) querystringParameters() (map[string]string, error) {
+	if err := validateUnitDP(o.UnitDP); err != nil {
+		return nil, err
+	}
+
+	params := o.ListOptions.querystringParameters()
+	addUnitDP(params, o.UnitDP)
+
+	return params, nil
+}
+
+// FindPrepaymentsWithOptions will get all Prepayments matching the given options
+func FindPrepaymentsWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *PrepaymentsOptions) (*Prepayments, error) {
+	if options == nil {
+		options = &PrepaymentsOptions{}
+	}
+
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindPrepaymentsModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+}
+
 // FindPrepayment will get a single prepayment - prepaymentID can be a GUID for an prepayment or an prepayment number
 func FindPrepayment(ctx context.Context, provider xerogolang.IProvider, session goth.Session, prepaymentID string) (*Prepayments, error) {
 	additionalHeaders := map[string]string{
@@ -160,3 +173,19 @@ func (p *Prepayments) Allocate(ctx context.Context, provider xerogolang.IProvide
 
 	return unmarshalPrepayment(prepaymentResponseBytes)
 }
+
+// RemoveAllocation removes a single allocation from a prepayment and returns the updated prepayment
+// allocationID must be the GUID of an allocation on the prepayment
+func (p *Prepayments) RemoveAllocation(ctx context.Context, provider xerogolang.IProvider, session goth.Session, allocationID string) (*Prepayments, error) {
+	additionalHeaders := map[string]string{
+		"Accept": "application/json",
+	}
+
+	_, err := provider.Remove(ctx, session, "Prepayments/"+p.Prepayments[0].PrepaymentID+"/Allocations/"+allocationID, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	//the delete response only contains the removed allocation so we fetch the prepayment again to get its new RemainingCredit
+	return FindPrepayment(ctx, provider, session, p.Prepayments[0].PrepaymentID)
+}
@@ -14,5 +14,5 @@ type Purchase struct {
 	COGSAccountCode string `json:"COGSAccountCode,omitempty"`
 
 	// Used as an override if the default Tax Code for the selected <AccountCode> is not correct – see TaxTypes.
-	TaxType string `json:"TaxType,omitempty"`
+	TaxType TaxType `json:"TaxType,omitempty"`
 }
@@ -4,11 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"time"
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -22,13 +22,13 @@ type PurchaseOrder struct {
 	Contact Contact `json:"Contact" xml:"Contact"`
 
 	// Date purchase order was issued – YYYY-MM-DD. If the Date element is not specified then it will default to the current date based on the timezone setting of the organisation
-	Date string `json:"DateString,omitempty" xml:"Date,omitempty"`
+	Date xerogolang.Date `json:"DateString,omitzero" xml:"Date,omitempty"`
 
 	// Date the goods are to be delivered – YYYY-MM-DD
-	DeliveryDate string `json:"DeliveryDateString,omitempty" xml:"DeliveryDate,omitempty"`
+	DeliveryDate xerogolang.Date `json:"DeliveryDateString,omitzero" xml:"DeliveryDate,omitempty"`
 
 	// Line amounts are exclusive of tax by default if you don’t specify this element. See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// Unique alpha numeric code identifying purchase order (when missing will auto-generate from your Organisation Invoice Settings)
 	PurchaseOrderNumber string `json:"PurchaseOrderNumber,omitempty" xml:"PurchaseOrderNumber,omitempty"`
@@ -43,7 +43,7 @@ type PurchaseOrder struct {
 	CurrencyCode string `json:"CurrencyCode,omitempty" xml:"CurrencyCode,omitempty"`
 
 	// See Purchase Order Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status PurchaseOrderStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// Boolean to set whether the purchase order should be marked as “sent”. This can be set only on purchase orders that have been approved or billed
 	SentToContact bool `json:"SentToContact,omitempty" xml:"SentToContact,omitempty"`
@@ -61,7 +61,7 @@ type PurchaseOrder struct {
 	DeliveryInstructions string `json:"DeliveryInstructions,omitempty" xml:"DeliveryInstructions,omitempty"`
 
 	// The date the goods are expected to arrive.
-	ExpectedArrivalDate string `json:"ExpectedArrivalDate,omitempty" xml:"ExpectedArrivalDate,omitempty"`
+	ExpectedArrivalDate xerogolang.Date `json:"ExpectedArrivalDate,omitzero" xml:"ExpectedArrivalDate,omitempty"`
 
 	// Xero generated unique identifier for purchase order
 	PurchaseOrderID string `json:"PurchaseOrderID,omitempty" xml:"PurchaseOrderID,omitempty"`
@@ -85,7 +85,7 @@ type PurchaseOrder struct {
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"-"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 }
 
 // PurchaseOrders contains a collection of PurchaseOrders
@@ -93,20 +93,6 @@ type PurchaseOrders struct {
 	PurchaseOrders []PurchaseOrder `json:"PurchaseOrders" xml:"PurchaseOrder"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (p *PurchaseOrders) convertDates() error {
-	var err error
-	for n := len(p.PurchaseOrders) - 1; n >= 0; n-- {
-		p.PurchaseOrders[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(p.PurchaseOrders[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalPurchaseOrder(purchaseOrderResponseBytes []byte) (*PurchaseOrders, error) {
 	var purchaseOrderResponse *PurchaseOrders
 	err := json.Unmarshal(purchaseOrderResponseBytes, &purchaseOrderResponse)
@@ -114,11 +100,6 @@ func unmarshalPurchaseOrder(purchaseOrderResponseBytes []byte) (*PurchaseOrders,
 		return nil, err
 	}
 
-	err = purchaseOrderResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return purchaseOrderResponse, err
 }
 
@@ -189,6 +170,57 @@ func FindPurchaseOrders(ctx context.Context, provider xerogolang.IProvider, sess
 	return FindPurchaseOrdersModifiedSince(ctx, provider, session, dayZero, querystringParameters)
 }
 
+// PurchaseOrdersOptions are the typed filters accepted by FindPurchaseOrdersWithOptions.
+// The PurchaseOrders endpoint does not support a where clause
+type PurchaseOrdersOptions struct {
+	ListOptions
+
+	// Only return purchase orders with this Status e.g. DRAFT, AUTHORISED
+	Status string
+
+	// Only return purchase orders dated on or after this date
+	DateFrom time.Time
+
+	// Only return purchase orders dated on or before this date
+	DateTo time.Time
+}
+
+func (o *PurchaseOrdersOptions) querystringParameters() (map[string]string, error) {
+	if !o.Where.IsZero() {
+		return nil, errors.New("the PurchaseOrders endpoint does not support a where clause")
+	}
+	if !o.DateFrom.IsZero() && !o.DateTo.IsZero() && o.DateTo.Before(o.DateFrom) {
+		return nil, errors.New("DateTo cannot be before DateFrom")
+	}
+
+	params := o.ListOptions.querystringParameters()
+	if o.Status != "" {
+		params["status"] = o.Status
+	}
+	if !o.DateFrom.IsZero() {
+		params["DateFrom"] = o.DateFrom.Format("2006-01-02")
+	}
+	if !o.DateTo.IsZero() {
+		params["DateTo"] = o.DateTo.Format("2006-01-02")
+	}
+
+	return params, nil
+}
+
+// FindPurchaseOrdersWithOptions will get all PurchaseOrders matching the given options
+func FindPurchaseOrdersWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *PurchaseOrdersOptions) (*PurchaseOrders, error) {
+	if options == nil {
+		options = &PurchaseOrdersOptions{}
+	}
+
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return FindPurchaseOrdersModifiedSince(ctx, provider, session, options.ModifiedSince, querystringParameters)
+}
+
 // FindPurchaseOrder will get a single purchaseOrder - purchaseOrderID can be a GUID for an purchaseOrder or an purchaseOrder number
 func FindPurchaseOrder(ctx context.Context, provider xerogolang.IProvider, session goth.Session, purchaseOrderID string) (*PurchaseOrders, error) {
 	additionalHeaders := map[string]string{
@@ -216,7 +248,7 @@ func GenerateExamplePurchaseOrder(contactID string) *PurchaseOrders {
 		Contact: Contact{
 			ContactID: contactID,
 		},
-		Date:            helpers.TodayRFC3339(),
+		Date:            xerogolang.Today(),
 		LineAmountTypes: "Exclusive",
 		LineItems:       []LineItem{},
 	}
@@ -8,7 +8,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -21,7 +20,7 @@ type Receipt struct {
 	Contact Contact `json:"Contact" xml:"Contact"`
 
 	// Date of receipt – YYYY-MM-DD
-	Date string `json:"Date" xml:"Date"`
+	Date xerogolang.Date `json:"Date" xml:"Date"`
 
 	// See LineItems
 	LineItems []LineItem `json:"LineItems" xml:"LineItems>LineItem"`
@@ -30,7 +29,7 @@ type Receipt struct {
 	Reference string `json:"Reference,omitempty" xml:"Reference,omitempty"`
 
 	// See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// Total of receipt excluding taxes
 	SubTotal decimal.Decimal `json:"SubTotal,omitempty" xml:"SubTotal,omitempty"`
@@ -51,7 +50,7 @@ type Receipt struct {
 	ReceiptNumber int `json:"ReceiptNumber,omitempty" xml:"ReceiptNumber,omitempty"`
 
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"-"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"-"`
 
 	// boolean to indicate if a receipt has an attachment
 	HasAttachments bool `json:"HasAttachments,omitempty" xml:"HasAttachments,omitempty"`
@@ -65,24 +64,6 @@ type Receipts struct {
 	Receipts []Receipt `json:"Receipts" xml:"Receipt"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (r *Receipts) convertDates() error {
-	var err error
-	for n := len(r.Receipts) - 1; n >= 0; n-- {
-		r.Receipts[n].Date, err = helpers.DotNetJSONTimeToRFC3339(r.Receipts[n].Date, false)
-		if err != nil {
-			return err
-		}
-		r.Receipts[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(r.Receipts[n].UpdatedDateUTC, true)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalReceipt(receiptResponseBytes []byte) (*Receipts, error) {
 	var receiptResponse *Receipts
 	err := json.Unmarshal(receiptResponseBytes, &receiptResponse)
@@ -90,11 +71,6 @@ func unmarshalReceipt(receiptResponseBytes []byte) (*Receipts, error) {
 		return nil, err
 	}
 
-	err = receiptResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return receiptResponse, err
 }
 
@@ -208,7 +184,7 @@ func GenerateExampleReceipt(userID string, contactID string) *Receipts {
 		Contact: Contact{
 			ContactID: contactID,
 		},
-		Date:            helpers.TodayRFC3339(),
+		Date:            xerogolang.Today(),
 		LineAmountTypes: "Inclusive",
 		LineItems:       []LineItem{},
 	}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 	"github.com/shopspring/decimal"
 )
 
@@ -14,7 +13,7 @@ import (
 type RepeatingInvoice struct {
 
 	// See Invoice Types
-	Type string `json:"Type,omitempty" xml:"Type,omitempty"`
+	Type InvoiceType `json:"Type,omitempty" xml:"Type,omitempty"`
 
 	// See Contacts
 	Contact Contact `json:"Contact" xml:"Contact"`
@@ -23,7 +22,7 @@ type RepeatingInvoice struct {
 	LineItems []LineItem `json:"LineItems,omitempty" xml:"LineItems>LineItem,omitempty"`
 
 	// Line amounts are exclusive of tax by default if you don’t specify this element. See Line Amount Types
-	LineAmountTypes string `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
+	LineAmountTypes LineAmountType `json:"LineAmountTypes,omitempty" xml:"LineAmountTypes,omitempty"`
 
 	// ACCREC only – additional reference number
 	Reference string `json:"Reference,omitempty" xml:"Reference,omitempty"`
@@ -61,30 +60,6 @@ type RepeatingInvoices struct {
 	RepeatingInvoices []RepeatingInvoice `json:"RepeatingInvoices,omitempty" xml:"RepeatingInvoice,omitempty"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (i *RepeatingInvoices) convertDates() error {
-	var err error
-	for n := len(i.RepeatingInvoices) - 1; n >= 0; n-- {
-		i.RepeatingInvoices[n].Schedule.EndDate, err = helpers.DotNetJSONTimeToRFC3339(i.RepeatingInvoices[n].Schedule.EndDate, false)
-		if err != nil {
-			return err
-		}
-
-		i.RepeatingInvoices[n].Schedule.StartDate, err = helpers.DotNetJSONTimeToRFC3339(i.RepeatingInvoices[n].Schedule.StartDate, false)
-		if err != nil {
-			return err
-		}
-
-		i.RepeatingInvoices[n].Schedule.NextScheduledDate, err = helpers.DotNetJSONTimeToRFC3339(i.RepeatingInvoices[n].Schedule.NextScheduledDate, false)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalRepeatingInvoices(repeatingInvoiceResponseBytes []byte) (*RepeatingInvoices, error) {
 	var repeatingInvoiceResponse *RepeatingInvoices
 	err := json.Unmarshal(repeatingInvoiceResponseBytes, &repeatingInvoiceResponse)
@@ -92,11 +67,6 @@ func unmarshalRepeatingInvoices(repeatingInvoiceResponseBytes []byte) (*Repeatin
 		return nil, err
 	}
 
-	err = repeatingInvoiceResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return repeatingInvoiceResponse, err
 }
 
@@ -7,7 +7,6 @@ import (
 
 	"github.com/markbates/goth"
 	"github.com/omniboost/xerogolang"
-	"github.com/omniboost/xerogolang/helpers"
 )
 
 // Report is an organised set of financial information
@@ -23,7 +22,7 @@ type Report struct {
 	//The date of the report
 	ReportDate string `json:"ReportDate,omitempty" xml:"ReportDate,omitempty"`
 	// Last modified date UTC format
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"UpdatedDateUTC,omitempty"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"UpdatedDateUTC,omitempty"`
 	//Attributes of the report
 	Attributes *[]ReportAttribute `json:"Attributes,omitempty" xml:"Attributes>Attribute,omitempty"`
 	//Rows on the report that may contain cells, Attributes, or other rows
@@ -35,22 +34,6 @@ type Reports struct {
 	Reports []Report `json:"Reports" xml:"Report"`
 }
 
-// The Xero API returns Dates based on the .Net JSON date format available at the time of development
-// We need to convert these to a more usable format - RFC3339 for consistency with what the API expects to recieve
-func (r *Reports) convertDates() error {
-	var err error
-	for n := len(r.Reports) - 1; n >= 0; n-- {
-		if r.Reports[n].UpdatedDateUTC != "" {
-			r.Reports[n].UpdatedDateUTC, err = helpers.DotNetJSONTimeToRFC3339(r.Reports[n].UpdatedDateUTC, true)
-		}
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func unmarshalReport(reportResponseBytes []byte) (*Reports, error) {
 	var reportResponse *Reports
 	err := json.Unmarshal(reportResponseBytes, &reportResponse)
@@ -58,11 +41,6 @@ func unmarshalReport(reportResponseBytes []byte) (*Reports, error) {
 		return nil, err
 	}
 
-	err = reportResponse.convertDates()
-	if err != nil {
-		return nil, err
-	}
-
 	return reportResponse, err
 }
 
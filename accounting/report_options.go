@@ -0,0 +1,375 @@
+package accounting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+)
+
+// reportDateFormat is the format reports expect their date parameters in
+const reportDateFormat = "2006-01-02"
+
+// ReportTimeframe is the length of each period when a report compares several periods
+type ReportTimeframe string
+
+const (
+	// TimeframeMonth compares months
+	TimeframeMonth ReportTimeframe = "MONTH"
+	// TimeframeQuarter compares quarters
+	TimeframeQuarter ReportTimeframe = "QUARTER"
+	// TimeframeYear compares years
+	TimeframeYear ReportTimeframe = "YEAR"
+)
+
+// Months returns the number of months in the timeframe - 0 if the timeframe is not known
+func (t ReportTimeframe) Months() int {
+	switch t {
+	case TimeframeMonth:
+		return 1
+	case TimeframeQuarter:
+		return 3
+	case TimeframeYear:
+		return 12
+	}
+	return 0
+}
+
+func (t ReportTimeframe) validate() error {
+	if t != "" && t.Months() == 0 {
+		return fmt.Errorf("timeframe must be MONTH, QUARTER or YEAR, not %q", string(t))
+	}
+	return nil
+}
+
+func validatePeriods(periods int, max int) error {
+	if periods != 0 && (periods < 1 || periods > max) {
+		return fmt.Errorf("periods must be between 1 and %d, not %d", max, periods)
+	}
+	return nil
+}
+
+func validateDateRange(fromDate time.Time, toDate time.Time) error {
+	if !fromDate.IsZero() && !toDate.IsZero() && toDate.Before(fromDate) {
+		return errors.New("toDate cannot be before fromDate")
+	}
+	return nil
+}
+
+func addReportDate(params map[string]string, name string, date time.Time) {
+	if !date.IsZero() {
+		params[name] = date.Format(reportDateFormat)
+	}
+}
+
+func addReportString(params map[string]string, name string, value string) {
+	if value != "" {
+		params[name] = value
+	}
+}
+
+// ProfitAndLossOptions are the options accepted by RunProfitAndLossWithOptions
+type ProfitAndLossOptions struct {
+	// Start of the report period
+	FromDate time.Time
+
+	// End of the report period
+	ToDate time.Time
+
+	// Number of periods to compare (between 1 and 11)
+	Periods int
+
+	// Length of each compared period
+	Timeframe ReportTimeframe
+
+	// Show the report for a single tracking option - TrackingOptionID needs TrackingCategoryID
+	TrackingCategoryID string
+	TrackingOptionID   string
+
+	// Show the report for a second tracking option - TrackingOptionID2 needs TrackingCategoryID2
+	TrackingCategoryID2 string
+	TrackingOptionID2   string
+
+	// Use the standard layout instead of a custom report layout
+	StandardLayout bool
+
+	// Only include cash transactions
+	PaymentsOnly bool
+}
+
+func (o *ProfitAndLossOptions) querystringParameters() (map[string]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := validateDateRange(o.FromDate, o.ToDate); err != nil {
+		return nil, err
+	}
+	if err := validatePeriods(o.Periods, 11); err != nil {
+		return nil, err
+	}
+	if err := o.Timeframe.validate(); err != nil {
+		return nil, err
+	}
+	if o.TrackingOptionID != "" && o.TrackingCategoryID == "" {
+		return nil, errors.New("trackingOptionID needs a trackingCategoryID")
+	}
+	if o.TrackingOptionID2 != "" && o.TrackingCategoryID2 == "" {
+		return nil, errors.New("trackingOptionID2 needs a trackingCategoryID2")
+	}
+
+	params := map[string]string{}
+	addReportDate(params, "fromDate", o.FromDate)
+	addReportDate(params, "toDate", o.ToDate)
+	if o.Periods != 0 {
+		params["periods"] = fmt.Sprint(o.Periods)
+	}
+	addReportString(params, "timeframe", string(o.Timeframe))
+	addReportString(params, "trackingCategoryID", o.TrackingCategoryID)
+	addReportString(params, "trackingOptionID", o.TrackingOptionID)
+	addReportString(params, "trackingCategoryID2", o.TrackingCategoryID2)
+	addReportString(params, "trackingOptionID2", o.TrackingOptionID2)
+	addFlag(params, "standardLayout", o.StandardLayout)
+	addFlag(params, "paymentsOnly", o.PaymentsOnly)
+	return params, nil
+}
+
+// RunProfitAndLossWithOptions will run the Profit And Loss Report with typed options and marshal the results to a Report Struct
+func RunProfitAndLossWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *ProfitAndLossOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunProfitAndLoss(ctx, provider, session, querystringParameters)
+}
+
+// BalanceSheetOptions are the options accepted by RunBalanceSheetWithOptions
+type BalanceSheetOptions struct {
+	// Date of the balance sheet
+	Date time.Time
+
+	// Number of periods to compare (between 1 and 11)
+	Periods int
+
+	// Length of each compared period
+	Timeframe ReportTimeframe
+
+	// Show the report for up to two tracking options
+	TrackingOptionID1 string
+	TrackingOptionID2 string
+
+	// Use the standard layout instead of a custom report layout
+	StandardLayout bool
+
+	// Only include cash transactions
+	PaymentsOnly bool
+}
+
+func (o *BalanceSheetOptions) querystringParameters() (map[string]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := validatePeriods(o.Periods, 11); err != nil {
+		return nil, err
+	}
+	if err := o.Timeframe.validate(); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+	addReportDate(params, "date", o.Date)
+	if o.Periods != 0 {
+		params["periods"] = fmt.Sprint(o.Periods)
+	}
+	addReportString(params, "timeframe", string(o.Timeframe))
+	addReportString(params, "trackingOptionID1", o.TrackingOptionID1)
+	addReportString(params, "trackingOptionID2", o.TrackingOptionID2)
+	addFlag(params, "standardLayout", o.StandardLayout)
+	addFlag(params, "paymentsOnly", o.PaymentsOnly)
+	return params, nil
+}
+
+// RunBalanceSheetWithOptions will run the Balance Sheet Report with typed options and marshal the results to a Report Struct
+func RunBalanceSheetWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *BalanceSheetOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunBalanceSheet(ctx, provider, session, querystringParameters)
+}
+
+// TrialBalanceOptions are the options accepted by RunTrialBalanceWithOptions
+type TrialBalanceOptions struct {
+	// Date of the trial balance
+	Date time.Time
+
+	// Only include cash transactions
+	PaymentsOnly bool
+}
+
+func (o *TrialBalanceOptions) querystringParameters() map[string]string {
+	if o == nil {
+		return nil
+	}
+	params := map[string]string{}
+	addReportDate(params, "date", o.Date)
+	addFlag(params, "paymentsOnly", o.PaymentsOnly)
+	return params
+}
+
+// RunTrialBalanceWithOptions will run the TrialBalance Report with typed options and marshal the results to a Report Struct
+func RunTrialBalanceWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *TrialBalanceOptions) (*Reports, error) {
+	return RunTrialBalance(ctx, provider, session, options.querystringParameters())
+}
+
+// AgedReportOptions are the options accepted by RunAgedPayablesByContactWithOptions and RunAgedReceivablesByContactWithOptions
+type AgedReportOptions struct {
+	// Date the amounts are aged at
+	Date time.Time
+
+	// Only show transactions from this date
+	FromDate time.Time
+
+	// Only show transactions up to this date
+	ToDate time.Time
+}
+
+func (o *AgedReportOptions) querystringParameters() (map[string]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := validateDateRange(o.FromDate, o.ToDate); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+	addReportDate(params, "date", o.Date)
+	addReportDate(params, "fromDate", o.FromDate)
+	addReportDate(params, "toDate", o.ToDate)
+	return params, nil
+}
+
+// RunAgedPayablesByContactWithOptions will run the Aged Payables By Contact Report with typed options and marshal the results to a Report Struct
+func RunAgedPayablesByContactWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string, options *AgedReportOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunAgedPayablesByContact(ctx, provider, session, contactID, querystringParameters)
+}
+
+// RunAgedReceivablesByContactWithOptions will run the Aged Receivables By Contact Report with typed options and marshal the results to a Report Struct
+func RunAgedReceivablesByContactWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string, options *AgedReportOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunAgedReceivablesByContact(ctx, provider, session, contactID, querystringParameters)
+}
+
+// BankReportOptions are the options accepted by RunBankStatementWithOptions and RunBankSummaryWithOptions
+type BankReportOptions struct {
+	// Start of the report period
+	FromDate time.Time
+
+	// End of the report period
+	ToDate time.Time
+}
+
+func (o *BankReportOptions) querystringParameters() (map[string]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := validateDateRange(o.FromDate, o.ToDate); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+	addReportDate(params, "fromDate", o.FromDate)
+	addReportDate(params, "toDate", o.ToDate)
+	return params, nil
+}
+
+// RunBankStatementWithOptions will run the Bank Statement Report with typed options and marshal the results to a Report Struct
+func RunBankStatementWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, bankAccountID string, options *BankReportOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunBankStatement(ctx, provider, session, bankAccountID, querystringParameters)
+}
+
+// RunBankSummaryWithOptions will run the Bank Summary Report with typed options and marshal the results to a Report Struct
+func RunBankSummaryWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *BankReportOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunBankSummary(ctx, provider, session, querystringParameters)
+}
+
+// BudgetSummaryOptions are the options accepted by RunBudgetSummaryWithOptions
+type BudgetSummaryOptions struct {
+	// Date the report starts from
+	Date time.Time
+
+	// Number of periods to compare (between 1 and 12)
+	Periods int
+
+	// Length of each compared period
+	Timeframe ReportTimeframe
+}
+
+func (o *BudgetSummaryOptions) querystringParameters() (map[string]string, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if err := validatePeriods(o.Periods, 12); err != nil {
+		return nil, err
+	}
+	if err := o.Timeframe.validate(); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+	addReportDate(params, "date", o.Date)
+	if o.Periods != 0 {
+		params["periods"] = fmt.Sprint(o.Periods)
+	}
+	//the budget summary takes the number of months in a period rather than its name
+	if o.Timeframe != "" {
+		params["timeframe"] = fmt.Sprint(o.Timeframe.Months())
+	}
+	return params, nil
+}
+
+// RunBudgetSummaryWithOptions will run the Budget Summary Report with typed options and marshal the results to a Report Struct
+func RunBudgetSummaryWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *BudgetSummaryOptions) (*Reports, error) {
+	querystringParameters, err := options.querystringParameters()
+	if err != nil {
+		return nil, err
+	}
+	return RunBudgetSummary(ctx, provider, session, querystringParameters)
+}
+
+// ExecutiveSummaryOptions are the options accepted by RunExecutiveSummaryWithOptions
+type ExecutiveSummaryOptions struct {
+	// Date of the month the summary is run for
+	Date time.Time
+}
+
+func (o *ExecutiveSummaryOptions) querystringParameters() map[string]string {
+	if o == nil {
+		return nil
+	}
+	params := map[string]string{}
+	addReportDate(params, "date", o.Date)
+	return params
+}
+
+// RunExecutiveSummaryWithOptions will run the Executive Summary Report with typed options and marshal the results to a Report Struct
+func RunExecutiveSummaryWithOptions(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *ExecutiveSummaryOptions) (*Reports, error) {
+	return RunExecutiveSummary(ctx, provider, session, options.querystringParameters())
+}
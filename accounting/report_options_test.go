@@ -0,0 +1,161 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ProfitAndLossOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var none *ProfitAndLossOptions
+	params, err := none.querystringParameters()
+	a.NoError(err)
+	a.Nil(params)
+
+	options := &ProfitAndLossOptions{
+		FromDate:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:             time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		Periods:            11,
+		Timeframe:          TimeframeMonth,
+		TrackingCategoryID: "tc-1",
+		TrackingOptionID:   "to-1",
+		PaymentsOnly:       true,
+	}
+	params, err = options.querystringParameters()
+	a.NoError(err)
+	a.Equal(map[string]string{
+		"fromDate":           "2024-01-01",
+		"toDate":             "2024-01-31",
+		"periods":            "11",
+		"timeframe":          "MONTH",
+		"trackingCategoryID": "tc-1",
+		"trackingOptionID":   "to-1",
+		"paymentsOnly":       "true",
+	}, params)
+
+	options.Periods = 12
+	_, err = options.querystringParameters()
+	a.EqualError(err, "periods must be between 1 and 11, not 12")
+	options.Periods = -1
+	_, err = options.querystringParameters()
+	a.EqualError(err, "periods must be between 1 and 11, not -1")
+	options.Periods = 0
+
+	options.Timeframe = "WEEK"
+	_, err = options.querystringParameters()
+	a.EqualError(err, `timeframe must be MONTH, QUARTER or YEAR, not "WEEK"`)
+	options.Timeframe = ""
+
+	options.TrackingCategoryID = ""
+	_, err = options.querystringParameters()
+	a.EqualError(err, "trackingOptionID needs a trackingCategoryID")
+	options.TrackingOptionID = ""
+
+	options.TrackingOptionID2 = "to-2"
+	_, err = options.querystringParameters()
+	a.EqualError(err, "trackingOptionID2 needs a trackingCategoryID2")
+	options.TrackingCategoryID2 = "tc-2"
+	params, err = options.querystringParameters()
+	a.NoError(err)
+	a.Equal("tc-2", params["trackingCategoryID2"])
+	a.Equal("to-2", params["trackingOptionID2"])
+
+	options.ToDate = time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)
+	_, err = options.querystringParameters()
+	a.EqualError(err, "toDate cannot be before fromDate")
+}
+
+func Test_BalanceSheetOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	options := &BalanceSheetOptions{
+		Date:              time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		Periods:           3,
+		Timeframe:         TimeframeQuarter,
+		TrackingOptionID1: "to-1",
+		StandardLayout:    true,
+	}
+	params, err := options.querystringParameters()
+	a.NoError(err)
+	a.Equal(map[string]string{
+		"date":              "2024-03-31",
+		"periods":           "3",
+		"timeframe":         "QUARTER",
+		"trackingOptionID1": "to-1",
+		"standardLayout":    "true",
+	}, params)
+
+	options.Periods = 12
+	_, err = options.querystringParameters()
+	a.EqualError(err, "periods must be between 1 and 11, not 12")
+	options.Periods = 0
+	options.Timeframe = "month"
+	_, err = options.querystringParameters()
+	a.EqualError(err, `timeframe must be MONTH, QUARTER or YEAR, not "month"`)
+}
+
+func Test_AgedAndBankReportOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	aged := &AgedReportOptions{
+		Date:     time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		FromDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	params, err := aged.querystringParameters()
+	a.NoError(err)
+	a.Equal(map[string]string{"date": "2024-03-31", "fromDate": "2024-01-01"}, params)
+	aged.ToDate = time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	_, err = aged.querystringParameters()
+	a.EqualError(err, "toDate cannot be before fromDate")
+
+	bank := &BankReportOptions{ToDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)}
+	params, err = bank.querystringParameters()
+	a.NoError(err)
+	a.Equal(map[string]string{"toDate": "2024-03-31"}, params)
+	bank.FromDate = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	_, err = bank.querystringParameters()
+	a.EqualError(err, "toDate cannot be before fromDate")
+
+	//a single day is a valid range
+	bank.FromDate = bank.ToDate
+	_, err = bank.querystringParameters()
+	a.NoError(err)
+}
+
+func Test_BudgetSummaryOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	options := &BudgetSummaryOptions{Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Periods: 12, Timeframe: TimeframeQuarter}
+	params, err := options.querystringParameters()
+	a.NoError(err)
+	a.Equal(map[string]string{"date": "2024-01-01", "periods": "12", "timeframe": "3"}, params)
+
+	options.Periods = 13
+	_, err = options.querystringParameters()
+	a.EqualError(err, "periods must be between 1 and 12, not 13")
+	options.Periods = 0
+	options.Timeframe = "DAY"
+	_, err = options.querystringParameters()
+	a.EqualError(err, `timeframe must be MONTH, QUARTER or YEAR, not "DAY"`)
+}
+
+func Test_SimpleReportOptions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(map[string]string{"date": "2024-03-31", "paymentsOnly": "true"},
+		(&TrialBalanceOptions{Date: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), PaymentsOnly: true}).querystringParameters())
+	a.Equal(map[string]string{}, (&TrialBalanceOptions{}).querystringParameters())
+	a.Equal(map[string]string{"date": "2024-03-01"},
+		(&ExecutiveSummaryOptions{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}).querystringParameters())
+
+	a.Equal(12, TimeframeYear.Months())
+	a.Equal(0, ReportTimeframe("WEEK").Months())
+}
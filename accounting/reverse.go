@@ -0,0 +1,157 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+)
+
+// ReverseOptions control how an invoice is reversed
+type ReverseOptions struct {
+	// Date of the credit note and its allocation - defaults to today
+	Date xerogolang.Date
+
+	// RemovePayments deletes the payments of the invoice first, so the credit note is allocated to the whole
+	// invoice. When false the payments are kept and the credit note is only allocated to the amount due,
+	// leaving the rest of its credit to be refunded
+	RemovePayments bool
+
+	// CreditNoteNumber of the credit note - Xero numbers sales credit notes when it is empty
+	CreditNoteNumber string
+
+	// Reference of the credit note - defaults to "Reversal of " and the invoice number
+	Reference string
+}
+
+// Reversal is the outcome of reversing an invoice
+type Reversal struct {
+	Invoice    Invoice
+	CreditNote CreditNote
+
+	// RemovedPayments are the payments deleted from the invoice
+	RemovedPayments []Payment
+
+	// Allocated is the amount of the credit note allocated to the invoice
+	Allocated decimal.Decimal
+}
+
+// ReversalCreditNote builds a credit note that mirrors the lines, tracking and tax of an invoice
+func ReversalCreditNote(invoice Invoice, options *ReverseOptions) (CreditNote, error) {
+	if options == nil {
+		options = &ReverseOptions{}
+	}
+	creditNoteType := invoice.Type.CreditNoteType()
+	if creditNoteType == "" {
+		return CreditNote{}, fmt.Errorf("Invoices/%s: cannot reverse an invoice of type %q", invoice.InvoiceID, invoice.Type)
+	}
+
+	reference := options.Reference
+	if reference == "" {
+		reference = "Reversal of " + invoice.InvoiceNumber
+	}
+	creditNote := CreditNote{
+		Type:             creditNoteType,
+		Contact:          Contact{ContactID: invoice.Contact.ContactID},
+		Date:             options.Date,
+		Status:           CreditNoteStatusAuthorised,
+		LineAmountTypes:  invoice.LineAmountTypes,
+		CurrencyCode:     invoice.CurrencyCode,
+		CurrencyRate:     invoice.CurrencyRate,
+		CreditNoteNumber: options.CreditNoteNumber,
+		Reference:        reference,
+		BrandingThemeID:  invoice.BrandingThemeID,
+		LineItems:        []LineItem{},
+	}
+	for _, line := range invoice.LineItems {
+		//the credit note gets lines of its own
+		line.LineItemID = ""
+		line.RepeatingInvoiceID = ""
+		creditNote.LineItems = append(creditNote.LineItems, line)
+	}
+	return creditNote, nil
+}
+
+// Reverse cancels an authorised or paid invoice with a credit note that mirrors it. The payments are removed
+// when asked, the credit note is created, allocated to the invoice and both get a history note pointing to the other.
+// When a step fails the Reversal holds what was done so far. Each write gets its own Idempotency-Key derived from
+// a key set with xerogolang.WithIdempotencyKey, so a reversal that failed can be tried again with the same key
+func (i *Invoice) Reverse(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *ReverseOptions) (*Reversal, error) {
+	if options == nil {
+		options = &ReverseOptions{}
+	}
+	if i.InvoiceID == "" {
+		return nil, fmt.Errorf("Invoices: the ID of the invoice is not set")
+	}
+	if i.Status != InvoiceStatusAuthorised && i.Status != InvoiceStatusPaid {
+		return nil, &TransitionError{Resource: "Invoices", ID: i.InvoiceID, From: string(i.Status), To: "REVERSED", Reason: "only authorised and paid invoices are reversed, void or delete the others"}
+	}
+	creditNote, err := ReversalCreditNote(*i, options)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal := &Reversal{Invoice: *i, RemovedPayments: []Payment{}}
+	amountDue := i.AmountDue
+	if options.RemovePayments && i.Payments != nil {
+		for _, payment := range *i.Payments {
+			if payment.Status == PaymentStatusDeleted {
+				continue
+			}
+			//Xero deletes a payment by setting its status
+			if _, err := updateStatus(xerogolang.WithIdempotencyStep(ctx, "payment:"+payment.PaymentID), provider, session, "Payments", "PaymentID", payment.PaymentID, "DELETED"); err != nil {
+				return reversal, fmt.Errorf("Payments/%s: %s", payment.PaymentID, err.Error())
+			}
+			reversal.RemovedPayments = append(reversal.RemovedPayments, payment)
+			amountDue = amountDue.Add(payment.Amount)
+		}
+	}
+
+	created, err := (&CreditNotes{CreditNotes: []CreditNote{creditNote}}).Create(xerogolang.WithIdempotencyStep(ctx, "credit-note"), provider, session)
+	if err != nil {
+		return reversal, err
+	}
+	reversal.CreditNote = created.CreditNotes[0]
+
+	allocate := amountDue
+	if reversal.CreditNote.Total != nil && allocate.GreaterThan(*reversal.CreditNote.Total) {
+		allocate = *reversal.CreditNote.Total
+	}
+	if allocate.IsPositive() {
+		date := options.Date
+		if date.IsZero() {
+			date = reversal.CreditNote.Date
+		}
+		allocations := Allocations{Allocations: []Allocation{{
+			AppliedAmount: allocate,
+			Date:          date,
+			Invoice:       InvoiceID{InvoiceID: i.InvoiceID},
+		}}}
+		if _, err := created.Allocate(xerogolang.WithIdempotencyStep(ctx, "allocation"), provider, session, allocations); err != nil {
+			return reversal, err
+		}
+		reversal.Allocated = allocate
+	}
+
+	creditNoteNumber := reversal.CreditNote.CreditNoteNumber
+	if creditNoteNumber == "" {
+		creditNoteNumber = reversal.CreditNote.CreditNoteID
+	}
+	invoiceNote := &HistoryRecords{HistoryRecords: []HistoryRecord{{Details: "Reversed by credit note " + creditNoteNumber}}}
+	if _, err := invoiceNote.Create(xerogolang.WithIdempotencyStep(ctx, "invoice-history"), provider, session, "Invoices", i.InvoiceID); err != nil {
+		return reversal, err
+	}
+	creditNoteNote := &HistoryRecords{HistoryRecords: []HistoryRecord{{Details: "Reverses invoice " + i.InvoiceNumber}}}
+	if _, err := creditNoteNote.Create(xerogolang.WithIdempotencyStep(ctx, "credit-note-history"), provider, session, "CreditNotes", reversal.CreditNote.CreditNoteID); err != nil {
+		return reversal, err
+	}
+
+	invoices, err := FindInvoice(ctx, provider, session, i.InvoiceID)
+	if err != nil {
+		return reversal, err
+	}
+	reversal.Invoice = invoices.Invoices[0]
+	return reversal, nil
+}
@@ -0,0 +1,88 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/omniboost/xerogolang"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_InvoiceReverse(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	invoice := &Invoice{
+		InvoiceID:       "inv-1",
+		InvoiceNumber:   "INV-0042",
+		Type:            "ACCREC",
+		Status:          InvoiceStatusAuthorised,
+		Contact:         Contact{ContactID: "contact-1", Name: "City Limousines"},
+		LineAmountTypes: "Exclusive",
+		LineItems: []LineItem{{
+			LineItemID:  "line-1",
+			Description: "Consulting",
+			AccountCode: "200",
+			TaxType:     "OUTPUT2",
+			LineAmount:  decimal.NewFromInt(100),
+			TaxAmount:   decimal.NewFromInt(15),
+			Tracking:    []TrackingCategory{{Name: "Region", Option: "North"}},
+		}},
+		Total:      decimal.NewFromInt(115),
+		AmountDue:  decimal.NewFromInt(75),
+		AmountPaid: decimal.NewFromInt(40),
+		Payments:   &[]Payment{{PaymentID: "pay-1", Amount: decimal.NewFromInt(40), Status: "AUTHORISED"}},
+	}
+
+	provider := &testProvider{responses: map[string]string{
+		"POST Payments/pay-1":              `{"Payments":[{"PaymentID":"pay-1","Status":"DELETED"}]}`,
+		"PUT CreditNotes":                  `{"CreditNotes":[{"CreditNoteID":"cn-1","CreditNoteNumber":"CN-0007","Total":115,"DateString":"2024-04-02T00:00:00"}]}`,
+		"PUT CreditNotes/cn-1/Allocations": `{"Allocations":[{"AppliedAmount":115}]}`,
+		"PUT Invoices/inv-1/history":       `{"HistoryRecords":[{"Details":"Reversed by credit note CN-0007"}]}`,
+		"PUT CreditNotes/cn-1/history":     `{"HistoryRecords":[{"Details":"Reverses invoice INV-0042"}]}`,
+		"GET Invoices/inv-1":               `{"Invoices":[{"InvoiceID":"inv-1","Status":"PAID"}]}`,
+	}}
+
+	reversal, err := invoice.Reverse(context.Background(), provider, nil, &ReverseOptions{RemovePayments: true})
+	a.NoError(err)
+	a.Len(reversal.RemovedPayments, 1)
+	a.Equal("115", reversal.Allocated.String())
+	a.Equal("CN-0007", reversal.CreditNote.CreditNoteNumber)
+	a.Equal(InvoiceStatusPaid, reversal.Invoice.Status)
+
+	a.Len(provider.requests, 6)
+	sent := &CreditNotes{}
+	a.NoError(json.Unmarshal([]byte(provider.requests[1].Body), sent))
+	creditNote := sent.CreditNotes[0]
+	a.Equal(CreditNoteTypeAccRecCredit, creditNote.Type)
+	a.Equal(CreditNoteStatusAuthorised, creditNote.Status)
+	a.Equal("Reversal of INV-0042", creditNote.Reference)
+	a.Equal("", creditNote.LineItems[0].LineItemID)
+	a.Equal(TaxType("OUTPUT2"), creditNote.LineItems[0].TaxType)
+	a.Equal(invoice.LineItems[0].Tracking, creditNote.LineItems[0].Tracking)
+	a.JSONEq(`{"Allocations":[{"AppliedAmount":"115","Date":"2024-04-02","Invoice":{"InvoiceID":"inv-1"}}]}`, provider.requests[2].Body)
+
+	//each write of a reversal tried with a key gets a key of its own
+	provider.requests = nil
+	_, err = invoice.Reverse(xerogolang.WithIdempotencyKey(context.Background(), "reverse-inv-1"), provider, nil, &ReverseOptions{RemovePayments: true})
+	a.NoError(err)
+	keys := []string{}
+	for _, request := range provider.requests {
+		keys = append(keys, request.IdempotencyKey)
+	}
+	a.Equal([]string{"reverse-inv-1:payment:pay-1", "reverse-inv-1:credit-note", "reverse-inv-1:allocation",
+		"reverse-inv-1:invoice-history", "reverse-inv-1:credit-note-history", "reverse-inv-1"}, keys)
+
+	//keeping the payments only allocates the amount due
+	provider.requests = nil
+	reversal, err = invoice.Reverse(context.Background(), provider, nil, nil)
+	a.NoError(err)
+	a.Empty(reversal.RemovedPayments)
+	a.Equal("75", reversal.Allocated.String())
+
+	draft := &Invoice{InvoiceID: "inv-2", Type: "ACCREC", Status: InvoiceStatusDraft}
+	_, err = draft.Reverse(context.Background(), provider, nil, nil)
+	a.IsType(&TransitionError{}, err)
+}
@@ -0,0 +1,383 @@
+package accounting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+)
+
+// InvoiceStatus is the status of an invoice
+type InvoiceStatus string
+
+// Invoice statuses
+const (
+	InvoiceStatusDraft      InvoiceStatus = "DRAFT"
+	InvoiceStatusSubmitted  InvoiceStatus = "SUBMITTED"
+	InvoiceStatusAuthorised InvoiceStatus = "AUTHORISED"
+	InvoiceStatusPaid       InvoiceStatus = "PAID"
+	InvoiceStatusVoided     InvoiceStatus = "VOIDED"
+	InvoiceStatusDeleted    InvoiceStatus = "DELETED"
+)
+
+// CreditNoteStatus is the status of a credit note
+type CreditNoteStatus string
+
+// Credit note statuses
+const (
+	CreditNoteStatusDraft      CreditNoteStatus = "DRAFT"
+	CreditNoteStatusSubmitted  CreditNoteStatus = "SUBMITTED"
+	CreditNoteStatusAuthorised CreditNoteStatus = "AUTHORISED"
+	CreditNoteStatusPaid       CreditNoteStatus = "PAID"
+	CreditNoteStatusVoided     CreditNoteStatus = "VOIDED"
+	CreditNoteStatusDeleted    CreditNoteStatus = "DELETED"
+)
+
+// PurchaseOrderStatus is the status of a purchase order
+type PurchaseOrderStatus string
+
+// Purchase order statuses
+const (
+	PurchaseOrderStatusDraft      PurchaseOrderStatus = "DRAFT"
+	PurchaseOrderStatusSubmitted  PurchaseOrderStatus = "SUBMITTED"
+	PurchaseOrderStatusAuthorised PurchaseOrderStatus = "AUTHORISED"
+	PurchaseOrderStatusBilled     PurchaseOrderStatus = "BILLED"
+	PurchaseOrderStatusDeleted    PurchaseOrderStatus = "DELETED"
+)
+
+// ExpenseClaimStatus is the status of an expense claim
+type ExpenseClaimStatus string
+
+// Expense claim statuses
+const (
+	ExpenseClaimStatusSubmitted  ExpenseClaimStatus = "SUBMITTED"
+	ExpenseClaimStatusAuthorised ExpenseClaimStatus = "AUTHORISED"
+	ExpenseClaimStatusPaid       ExpenseClaimStatus = "PAID"
+	ExpenseClaimStatusVoided     ExpenseClaimStatus = "VOIDED"
+	ExpenseClaimStatusDeleted    ExpenseClaimStatus = "DELETED"
+)
+
+// BankTransactionStatus is the status of a bank transaction
+type BankTransactionStatus string
+
+// Bank transaction statuses
+const (
+	BankTransactionStatusAuthorised BankTransactionStatus = "AUTHORISED"
+	BankTransactionStatusDeleted    BankTransactionStatus = "DELETED"
+)
+
+// the moves Xero allows through the API - PAID is reached by paying a document, not by setting it
+var (
+	invoiceTransitions = map[InvoiceStatus][]InvoiceStatus{
+		InvoiceStatusDraft:      {InvoiceStatusSubmitted, InvoiceStatusAuthorised, InvoiceStatusDeleted},
+		InvoiceStatusSubmitted:  {InvoiceStatusDraft, InvoiceStatusAuthorised, InvoiceStatusDeleted},
+		InvoiceStatusAuthorised: {InvoiceStatusVoided},
+	}
+	creditNoteTransitions = map[CreditNoteStatus][]CreditNoteStatus{
+		CreditNoteStatusDraft:      {CreditNoteStatusSubmitted, CreditNoteStatusAuthorised, CreditNoteStatusDeleted},
+		CreditNoteStatusSubmitted:  {CreditNoteStatusDraft, CreditNoteStatusAuthorised, CreditNoteStatusDeleted},
+		CreditNoteStatusAuthorised: {CreditNoteStatusVoided},
+	}
+	purchaseOrderTransitions = map[PurchaseOrderStatus][]PurchaseOrderStatus{
+		PurchaseOrderStatusDraft:      {PurchaseOrderStatusSubmitted, PurchaseOrderStatusAuthorised, PurchaseOrderStatusDeleted},
+		PurchaseOrderStatusSubmitted:  {PurchaseOrderStatusDraft, PurchaseOrderStatusAuthorised, PurchaseOrderStatusDeleted},
+		PurchaseOrderStatusAuthorised: {PurchaseOrderStatusBilled, PurchaseOrderStatusDeleted},
+		PurchaseOrderStatusBilled:     {PurchaseOrderStatusAuthorised},
+	}
+	expenseClaimTransitions = map[ExpenseClaimStatus][]ExpenseClaimStatus{
+		ExpenseClaimStatusSubmitted:  {ExpenseClaimStatusAuthorised, ExpenseClaimStatusDeleted},
+		ExpenseClaimStatusAuthorised: {ExpenseClaimStatusSubmitted, ExpenseClaimStatusVoided},
+	}
+	bankTransactionTransitions = map[BankTransactionStatus][]BankTransactionStatus{
+		BankTransactionStatusAuthorised: {BankTransactionStatusDeleted},
+	}
+)
+
+// CanTransitionTo reports whether Xero allows an invoice to move from s to status
+func (s InvoiceStatus) CanTransitionTo(status InvoiceStatus) bool {
+	for _, allowed := range invoiceTransitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether Xero allows a credit note to move from s to status
+func (s CreditNoteStatus) CanTransitionTo(status CreditNoteStatus) bool {
+	for _, allowed := range creditNoteTransitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether Xero allows a purchase order to move from s to status
+func (s PurchaseOrderStatus) CanTransitionTo(status PurchaseOrderStatus) bool {
+	for _, allowed := range purchaseOrderTransitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether Xero allows an expense claim to move from s to status
+func (s ExpenseClaimStatus) CanTransitionTo(status ExpenseClaimStatus) bool {
+	for _, allowed := range expenseClaimTransitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo reports whether Xero allows a bank transaction to move from s to status
+func (s BankTransactionStatus) CanTransitionTo(status BankTransactionStatus) bool {
+	for _, allowed := range bankTransactionTransitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionError is returned when a document cannot move from its status to another
+type TransitionError struct {
+	// Resource is the endpoint of the document e.g. Invoices
+	Resource string
+	ID       string
+	From     string
+	To       string
+
+	// Reason explains why a move Xero normally allows is refused, it is empty when the move is never allowed
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	message := fmt.Sprintf("%s/%s cannot move from %s to %s", e.Resource, e.ID, e.From, e.To)
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+// checkTransition returns a TransitionError when the move is not allowed
+func checkTransition(resource string, id string, from string, to string, allowed bool, reason string) error {
+	if id == "" {
+		return fmt.Errorf("%s: the ID of the document is not set", resource)
+	}
+	if from == "" {
+		return &TransitionError{Resource: resource, ID: id, To: to, Reason: "the status is not known, read the document first"}
+	}
+	if !allowed || reason != "" {
+		return &TransitionError{Resource: resource, ID: id, From: from, To: to, Reason: reason}
+	}
+	return nil
+}
+
+// updateStatus sends an update holding nothing but the ID and the new status of a document
+func updateStatus(ctx context.Context, provider xerogolang.IProvider, session goth.Session, resource string, idField string, id string, status string) ([]byte, error) {
+	additionalHeaders := map[string]string{
+		"Accept":       "application/json",
+		"Content-Type": "application/json",
+	}
+
+	body, err := json.Marshal(map[string][]map[string]string{
+		resource: {{idField: id, "Status": status}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.Update(ctx, session, resource+"/"+id, additionalHeaders, body)
+}
+
+// CheckTransition returns a *TransitionError when the invoice cannot move to status
+func (i *Invoice) CheckTransition(status InvoiceStatus) error {
+	reason := ""
+	if status == InvoiceStatusVoided && (i.AmountPaid.IsPositive() || i.AmountCredited.IsPositive()) {
+		reason = "it has payments or credits allocated, remove them first"
+	}
+	return checkTransition("Invoices", i.InvoiceID, string(i.Status), string(status), i.Status.CanTransitionTo(status), reason)
+}
+
+// Transition moves the invoice to status after checking Xero allows it
+func (i *Invoice) Transition(ctx context.Context, provider xerogolang.IProvider, session goth.Session, status InvoiceStatus) (*Invoices, error) {
+	if err := i.CheckTransition(status); err != nil {
+		return nil, err
+	}
+	invoiceResponseBytes, err := updateStatus(ctx, provider, session, "Invoices", "InvoiceID", i.InvoiceID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	i.Status = status
+	return unmarshalInvoice(invoiceResponseBytes)
+}
+
+// Submit moves a draft invoice to awaiting approval
+func (i *Invoice) Submit(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Invoices, error) {
+	return i.Transition(ctx, provider, session, InvoiceStatusSubmitted)
+}
+
+// Authorise approves the invoice
+func (i *Invoice) Authorise(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Invoices, error) {
+	return i.Transition(ctx, provider, session, InvoiceStatusAuthorised)
+}
+
+// Void voids an authorised invoice without payments
+func (i *Invoice) Void(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Invoices, error) {
+	return i.Transition(ctx, provider, session, InvoiceStatusVoided)
+}
+
+// Delete deletes a draft or submitted invoice
+func (i *Invoice) Delete(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Invoices, error) {
+	return i.Transition(ctx, provider, session, InvoiceStatusDeleted)
+}
+
+// CheckTransition returns a *TransitionError when the credit note cannot move to status
+func (c *CreditNote) CheckTransition(status CreditNoteStatus) error {
+	reason := ""
+	if status == CreditNoteStatusVoided && c.Allocations != nil && len(*c.Allocations) > 0 {
+		reason = "it is allocated, remove the allocations first"
+	}
+	return checkTransition("CreditNotes", c.CreditNoteID, string(c.Status), string(status), c.Status.CanTransitionTo(status), reason)
+}
+
+// Transition moves the credit note to status after checking Xero allows it
+func (c *CreditNote) Transition(ctx context.Context, provider xerogolang.IProvider, session goth.Session, status CreditNoteStatus) (*CreditNotes, error) {
+	if err := c.CheckTransition(status); err != nil {
+		return nil, err
+	}
+	creditNoteResponseBytes, err := updateStatus(ctx, provider, session, "CreditNotes", "CreditNoteID", c.CreditNoteID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	c.Status = status
+	return unmarshalCreditNote(creditNoteResponseBytes)
+}
+
+// Submit moves a draft credit note to awaiting approval
+func (c *CreditNote) Submit(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*CreditNotes, error) {
+	return c.Transition(ctx, provider, session, CreditNoteStatusSubmitted)
+}
+
+// Authorise approves the credit note
+func (c *CreditNote) Authorise(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*CreditNotes, error) {
+	return c.Transition(ctx, provider, session, CreditNoteStatusAuthorised)
+}
+
+// Void voids an authorised credit note that is not allocated
+func (c *CreditNote) Void(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*CreditNotes, error) {
+	return c.Transition(ctx, provider, session, CreditNoteStatusVoided)
+}
+
+// Delete deletes a draft or submitted credit note
+func (c *CreditNote) Delete(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*CreditNotes, error) {
+	return c.Transition(ctx, provider, session, CreditNoteStatusDeleted)
+}
+
+// CheckTransition returns a *TransitionError when the purchase order cannot move to status
+func (p *PurchaseOrder) CheckTransition(status PurchaseOrderStatus) error {
+	return checkTransition("PurchaseOrders", p.PurchaseOrderID, string(p.Status), string(status), p.Status.CanTransitionTo(status), "")
+}
+
+// Transition moves the purchase order to status after checking Xero allows it
+func (p *PurchaseOrder) Transition(ctx context.Context, provider xerogolang.IProvider, session goth.Session, status PurchaseOrderStatus) (*PurchaseOrders, error) {
+	if err := p.CheckTransition(status); err != nil {
+		return nil, err
+	}
+	purchaseOrderResponseBytes, err := updateStatus(ctx, provider, session, "PurchaseOrders", "PurchaseOrderID", p.PurchaseOrderID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	p.Status = status
+	return unmarshalPurchaseOrder(purchaseOrderResponseBytes)
+}
+
+// Submit moves a draft purchase order to awaiting approval
+func (p *PurchaseOrder) Submit(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*PurchaseOrders, error) {
+	return p.Transition(ctx, provider, session, PurchaseOrderStatusSubmitted)
+}
+
+// Authorise approves the purchase order
+func (p *PurchaseOrder) Authorise(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*PurchaseOrders, error) {
+	return p.Transition(ctx, provider, session, PurchaseOrderStatusAuthorised)
+}
+
+// MarkBilled marks an authorised purchase order as billed
+func (p *PurchaseOrder) MarkBilled(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*PurchaseOrders, error) {
+	return p.Transition(ctx, provider, session, PurchaseOrderStatusBilled)
+}
+
+// Delete deletes a purchase order that is not billed
+func (p *PurchaseOrder) Delete(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*PurchaseOrders, error) {
+	return p.Transition(ctx, provider, session, PurchaseOrderStatusDeleted)
+}
+
+// CheckTransition returns a *TransitionError when the expense claim cannot move to status
+func (e *ExpenseClaim) CheckTransition(status ExpenseClaimStatus) error {
+	reason := ""
+	if status == ExpenseClaimStatusVoided && e.AmountPaid.IsPositive() {
+		reason = "it has payments, remove them first"
+	}
+	return checkTransition("ExpenseClaims", e.ExpenseClaimID, string(e.Status), string(status), e.Status.CanTransitionTo(status), reason)
+}
+
+// Transition moves the expense claim to status after checking Xero allows it
+func (e *ExpenseClaim) Transition(ctx context.Context, provider xerogolang.IProvider, session goth.Session, status ExpenseClaimStatus) (*ExpenseClaims, error) {
+	if err := e.CheckTransition(status); err != nil {
+		return nil, err
+	}
+	expenseClaimResponseBytes, err := updateStatus(ctx, provider, session, "ExpenseClaims", "ExpenseClaimID", e.ExpenseClaimID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	e.Status = status
+	return unmarshalExpenseClaim(expenseClaimResponseBytes)
+}
+
+// Authorise approves a submitted expense claim
+func (e *ExpenseClaim) Authorise(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ExpenseClaims, error) {
+	return e.Transition(ctx, provider, session, ExpenseClaimStatusAuthorised)
+}
+
+// Void voids an authorised expense claim without payments
+func (e *ExpenseClaim) Void(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ExpenseClaims, error) {
+	return e.Transition(ctx, provider, session, ExpenseClaimStatusVoided)
+}
+
+// Delete deletes a submitted expense claim
+func (e *ExpenseClaim) Delete(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ExpenseClaims, error) {
+	return e.Transition(ctx, provider, session, ExpenseClaimStatusDeleted)
+}
+
+// CheckTransition returns a *TransitionError when the bank transaction cannot move to status
+func (b *BankTransaction) CheckTransition(status BankTransactionStatus) error {
+	reason := ""
+	if status == BankTransactionStatusDeleted && b.IsReconciled {
+		reason = "it is reconciled, unreconcile it first"
+	}
+	return checkTransition("BankTransactions", b.BankTransactionID, string(b.Status), string(status), b.Status.CanTransitionTo(status), reason)
+}
+
+// Transition moves the bank transaction to status after checking Xero allows it
+func (b *BankTransaction) Transition(ctx context.Context, provider xerogolang.IProvider, session goth.Session, status BankTransactionStatus) (*BankTransactions, error) {
+	if err := b.CheckTransition(status); err != nil {
+		return nil, err
+	}
+	bankTransactionResponseBytes, err := updateStatus(ctx, provider, session, "BankTransactions", "BankTransactionID", b.BankTransactionID, string(status))
+	if err != nil {
+		return nil, err
+	}
+	b.Status = status
+	return unmarshalBankTransaction(bankTransactionResponseBytes)
+}
+
+// Delete deletes a bank transaction that is not reconciled
+func (b *BankTransaction) Delete(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*BankTransactions, error) {
+	return b.Transition(ctx, provider, session, BankTransactionStatusDeleted)
+}
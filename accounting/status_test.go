@@ -0,0 +1,52 @@
+package accounting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StatusTransitions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.True(InvoiceStatusDraft.CanTransitionTo(InvoiceStatusAuthorised))
+	a.True(InvoiceStatusAuthorised.CanTransitionTo(InvoiceStatusVoided))
+	a.False(InvoiceStatusAuthorised.CanTransitionTo(InvoiceStatusDeleted))
+	a.False(InvoiceStatusPaid.CanTransitionTo(InvoiceStatusVoided))
+	a.True(PurchaseOrderStatusAuthorised.CanTransitionTo(PurchaseOrderStatusBilled))
+	a.False(ExpenseClaimStatusPaid.CanTransitionTo(ExpenseClaimStatusVoided))
+	a.False(BankTransactionStatusDeleted.CanTransitionTo(BankTransactionStatusAuthorised))
+}
+
+func Test_InvoiceTransition(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	provider := &testProvider{responses: map[string]string{
+		"POST Invoices/inv-1": `{"Invoices":[{"InvoiceID":"inv-1","Status":"VOIDED"}]}`,
+	}}
+
+	invoice := &Invoice{InvoiceID: "inv-1", Status: InvoiceStatusAuthorised, AmountPaid: decimal.NewFromInt(10)}
+	_, err := invoice.Delete(context.Background(), provider, nil)
+	a.EqualError(err, "Invoices/inv-1 cannot move from AUTHORISED to DELETED")
+	_, err = invoice.Void(context.Background(), provider, nil)
+	transition, ok := err.(*TransitionError)
+	a.True(ok)
+	a.Equal("it has payments or credits allocated, remove them first", transition.Reason)
+	a.Empty(provider.requests)
+
+	invoice.AmountPaid = decimal.Zero
+	invoices, err := invoice.Void(context.Background(), provider, nil)
+	a.NoError(err)
+	a.Equal(InvoiceStatusVoided, invoices.Invoices[0].Status)
+	a.Equal(InvoiceStatusVoided, invoice.Status)
+	a.Len(provider.requests, 1)
+	a.JSONEq(`{"Invoices":[{"InvoiceID":"inv-1","Status":"VOIDED"}]}`, provider.requests[0].Body)
+
+	unread := &BankTransaction{BankTransactionID: "bt-1"}
+	_, err = unread.Delete(context.Background(), provider, nil)
+	a.IsType(&TransitionError{}, err)
+}
@@ -17,13 +17,13 @@ type TaxRate struct {
 	Name string `json:"Name,omitempty" xml:"Name,omitempty"`
 
 	// See Tax Types – can only be used on update calls
-	TaxType string `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
+	TaxType TaxType `json:"TaxType,omitempty" xml:"TaxType,omitempty"`
 
 	// See TaxComponents
 	TaxComponents []TaxComponent `json:"TaxComponents,omitempty" xml:"TaxComponents>TaxComponent,omitempty"`
 
 	// See Status Codes
-	Status string `json:"Status,omitempty" xml:"Status,omitempty"`
+	Status TaxRateStatus `json:"Status,omitempty" xml:"Status,omitempty"`
 
 	// See ReportTaxTypes
 	ReportTaxType string `json:"ReportTaxType" xml:"ReportTaxType"`
@@ -25,7 +25,7 @@ type User struct {
 	LastName string `json:"LastName,omitempty" xml:"LastName,omitempty"`
 
 	// Timestamp of last change to user
-	UpdatedDateUTC string `json:"UpdatedDateUTC,omitempty" xml:"UpdatedDateUTC,omitempty"`
+	UpdatedDateUTC xerogolang.DateTime `json:"UpdatedDateUTC,omitzero" xml:"UpdatedDateUTC,omitempty"`
 
 	// Boolean to indicate if user is the subscriber
 	IsSubscriber bool `json:"IsSubscriber,omitempty" xml:"IsSubscriber,omitempty"`
@@ -0,0 +1,340 @@
+package accounting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+)
+
+// ValidationError is a field of a payload Xero would reject
+type ValidationError struct {
+	// Field is the path to the field e.g. Invoices[0].LineItems[1].Description
+	Field string
+
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors holds every problem found in a payload
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for n, err := range e {
+		messages[n] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Field returns the errors of a field
+func (e ValidationErrors) Field(field string) []ValidationError {
+	found := []ValidationError{}
+	for _, err := range e {
+		if err.Field == field {
+			found = append(found, err)
+		}
+	}
+	return found
+}
+
+// ReferenceData holds the account codes and tax types of an organisation. Find it once and pass it
+// to every validation rather than reading it for each payload
+type ReferenceData struct {
+	AccountCodes map[string]bool
+	TaxTypes     map[TaxType]bool
+}
+
+// NewReferenceData collects the codes that can be used on documents. Archived accounts and tax rates
+// that are no longer active are left out as Xero rejects them as well
+func NewReferenceData(accounts []Account, taxRates []TaxRate) *ReferenceData {
+	r := &ReferenceData{AccountCodes: map[string]bool{}, TaxTypes: map[TaxType]bool{}}
+	for _, account := range accounts {
+		if account.Code != "" && account.Status != AccountStatusArchived {
+			r.AccountCodes[account.Code] = true
+		}
+	}
+	for _, taxRate := range taxRates {
+		if taxRate.TaxType != "" && (taxRate.Status == "" || taxRate.Status == TaxRateStatusActive) {
+			r.TaxTypes[taxRate.TaxType] = true
+		}
+	}
+	return r
+}
+
+// FindReferenceData reads the accounts and tax rates of the organisation
+func FindReferenceData(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ReferenceData, error) {
+	accounts, err := FindAccounts(ctx, provider, session, nil)
+	if err != nil {
+		return nil, err
+	}
+	taxRates, err := FindTaxRates(ctx, provider, session, nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewReferenceData(accounts.Accounts, taxRates.TaxRates), nil
+}
+
+// ValidateOptions control the checks done by the Validate methods
+type ValidateOptions struct {
+	// Reference is used to check account codes and tax types - they are not checked when nil
+	Reference *ReferenceData
+}
+
+func (o *ValidateOptions) validator() *validator {
+	v := &validator{errors: ValidationErrors{}}
+	if o != nil {
+		v.reference = o.Reference
+	}
+	return v
+}
+
+// validator collects the errors found while walking a payload
+type validator struct {
+	errors    ValidationErrors
+	reference *ReferenceData
+}
+
+func (v *validator) add(field string, format string, args ...interface{}) {
+	v.errors = append(v.errors, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
+
+func (v *validator) required(field string, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) maxLength(field string, value string, max int) {
+	if length := utf8.RuneCountInString(value); length > max {
+		v.add(field, "is %d characters long, the maximum is %d", length, max)
+	}
+}
+
+func (v *validator) oneOf(field string, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.add(field, "%q is not one of %s", value, strings.Join(allowed, ", "))
+}
+
+func (v *validator) accountCode(field string, code string) {
+	if code != "" && v.reference != nil && !v.reference.AccountCodes[code] {
+		v.add(field, "account code %q does not exist", code)
+	}
+}
+
+func (v *validator) taxType(field string, taxType TaxType) {
+	if taxType != "" && v.reference != nil && !v.reference.TaxTypes[taxType] {
+		v.add(field, "tax type %q does not exist", taxType)
+	}
+}
+
+func (v *validator) tracking(field string, tracking []TrackingCategory) {
+	if len(tracking) > 2 {
+		v.add(field, "has %d tracking categories, the maximum is 2", len(tracking))
+	}
+}
+
+// contact checks the contact of a document. A contact is needed when the document is created,
+// an update can leave it out
+func (v *validator) contact(field string, contact Contact, update bool) {
+	if !update && contact.ContactID == "" && strings.TrimSpace(contact.Name) == "" {
+		v.add(field, "a ContactID or Name is required")
+	}
+	v.maxLength(field+".Name", contact.Name, 255)
+}
+
+func (v *validator) lineItems(field string, lineItems []LineItem, discounts bool) {
+	for n, lineItem := range lineItems {
+		path := fmt.Sprintf("%s[%d]", field, n)
+		//Xero takes the description of the item when there is an ItemCode
+		if lineItem.ItemCode == "" {
+			v.required(path+".Description", lineItem.Description)
+		}
+		v.maxLength(path+".Description", lineItem.Description, 4000)
+		v.tracking(path+".Tracking", lineItem.Tracking)
+		if !discounts && !lineItem.DiscountRate.IsZero() {
+			v.add(path+".DiscountRate", "discounts are not supported on bills and supplier credit notes")
+		}
+		v.accountCode(path+".AccountCode", lineItem.AccountCode)
+		v.taxType(path+".TaxType", lineItem.TaxType)
+	}
+}
+
+// Validate checks the invoices for problems Xero would reject them for
+func (i *Invoices) Validate() error {
+	return i.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the invoices, optionally against the reference data of the organisation
+func (i *Invoices) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, invoice := range i.Invoices {
+		path := fmt.Sprintf("Invoices[%d]", n)
+		v.oneOf(path+".Type", string(invoice.Type), "ACCREC", "ACCPAY")
+		v.contact(path+".Contact", invoice.Contact, invoice.InvoiceID != "")
+		v.maxLength(path+".InvoiceNumber", invoice.InvoiceNumber, 255)
+		v.maxLength(path+".Reference", invoice.Reference, 255)
+		v.lineItems(path+".LineItems", invoice.LineItems, !invoice.Type.IsPayable())
+	}
+	return v.err()
+}
+
+// Validate checks the credit notes for problems Xero would reject them for
+func (c *CreditNotes) Validate() error {
+	return c.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the credit notes, optionally against the reference data of the organisation
+func (c *CreditNotes) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, creditNote := range c.CreditNotes {
+		path := fmt.Sprintf("CreditNotes[%d]", n)
+		v.oneOf(path+".Type", string(creditNote.Type), "ACCRECCREDIT", "ACCPAYCREDIT")
+		v.contact(path+".Contact", creditNote.Contact, creditNote.CreditNoteID != "")
+		v.maxLength(path+".CreditNoteNumber", creditNote.CreditNoteNumber, 255)
+		v.maxLength(path+".Reference", creditNote.Reference, 255)
+		v.lineItems(path+".LineItems", creditNote.LineItems, !creditNote.Type.IsPayable())
+	}
+	return v.err()
+}
+
+// Validate checks the bank transactions for problems Xero would reject them for
+func (b *BankTransactions) Validate() error {
+	return b.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the bank transactions, optionally against the reference data of the organisation
+func (b *BankTransactions) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, bankTransaction := range b.BankTransactions {
+		path := fmt.Sprintf("BankTransactions[%d]", n)
+		v.oneOf(path+".Type", string(bankTransaction.Type), "RECEIVE", "SPEND", "RECEIVE-OVERPAYMENT", "SPEND-OVERPAYMENT", "RECEIVE-PREPAYMENT", "SPEND-PREPAYMENT")
+		update := bankTransaction.BankTransactionID != ""
+		v.contact(path+".Contact", bankTransaction.Contact, update)
+		if !update && bankTransaction.BankAccount.AccountID == "" && bankTransaction.BankAccount.Code == "" {
+			v.add(path+".BankAccount", "an AccountID or Code is required")
+		}
+		v.accountCode(path+".BankAccount.Code", bankTransaction.BankAccount.Code)
+		v.maxLength(path+".Reference", bankTransaction.Reference, 255)
+		v.lineItems(path+".LineItems", bankTransaction.LineItems, true)
+	}
+	return v.err()
+}
+
+// Validate checks the manual journals for problems Xero would reject them for
+func (m *ManualJournals) Validate() error {
+	return m.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the manual journals, optionally against the reference data of the organisation
+func (m *ManualJournals) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, manualJournal := range m.ManualJournals {
+		path := fmt.Sprintf("ManualJournals[%d]", n)
+		v.required(path+".Narration", manualJournal.Narration)
+		if len(manualJournal.JournalLines) < 2 {
+			v.add(path+".JournalLines", "at least two lines are required")
+		}
+		for l, line := range manualJournal.JournalLines {
+			linePath := fmt.Sprintf("%s.JournalLines[%d]", path, l)
+			v.required(linePath+".AccountCode", line.AccountCode)
+			v.accountCode(linePath+".AccountCode", line.AccountCode)
+			v.taxType(linePath+".TaxType", line.TaxType)
+			v.maxLength(linePath+".Description", line.Description, 4000)
+			v.tracking(linePath+".Tracking", line.Tracking)
+		}
+		//journals whose tax is not known are left to Xero
+		if imbalance, err := manualJournal.Imbalance(nil); err == nil && !imbalance.IsZero() {
+			v.add(path+".JournalLines", "debits and credits differ by %s", imbalance.StringFixed(2))
+		}
+	}
+	return v.err()
+}
+
+// Validate checks the contacts for problems Xero would reject them for
+func (c *Contacts) Validate() error {
+	return c.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the contacts, optionally against the reference data of the organisation
+func (c *Contacts) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, contact := range c.Contacts {
+		path := fmt.Sprintf("Contacts[%d]", n)
+		if contact.ContactID == "" {
+			v.required(path+".Name", contact.Name)
+		}
+		v.maxLength(path+".Name", contact.Name, 255)
+		v.maxLength(path+".ContactNumber", contact.ContactNumber, 50)
+		v.maxLength(path+".AccountNumber", contact.AccountNumber, 50)
+		v.maxLength(path+".FirstName", contact.FirstName, 255)
+		v.maxLength(path+".LastName", contact.LastName, 255)
+		v.maxLength(path+".EmailAddress", contact.EmailAddress, 255)
+		v.maxLength(path+".TaxNumber", contact.TaxNumber, 50)
+		v.taxType(path+".AccountsReceivableTaxType", contact.AccountsReceivableTaxType)
+		v.taxType(path+".AccountsPayableTaxType", contact.AccountsPayableTaxType)
+		v.accountCode(path+".SalesDefaultAccountCode", contact.SalesDefaultAccountCode)
+		v.accountCode(path+".PurchasesDefaultAccountCode", contact.PurchasesDefaultAccountCode)
+		if contact.Addresses == nil {
+			continue
+		}
+		for a, address := range *contact.Addresses {
+			addressPath := fmt.Sprintf("%s.Addresses[%d]", path, a)
+			v.maxLength(addressPath+".AddressLine1", address.AddressLine1, 500)
+			v.maxLength(addressPath+".AddressLine2", address.AddressLine2, 500)
+			v.maxLength(addressPath+".AddressLine3", address.AddressLine3, 500)
+			v.maxLength(addressPath+".AddressLine4", address.AddressLine4, 500)
+			v.maxLength(addressPath+".City", address.City, 255)
+			v.maxLength(addressPath+".Region", address.Region, 255)
+			v.maxLength(addressPath+".PostalCode", address.PostalCode, 50)
+			v.maxLength(addressPath+".Country", address.Country, 50)
+			v.maxLength(addressPath+".AttentionTo", address.AttentionTo, 255)
+		}
+	}
+	return v.err()
+}
+
+func (v *validator) details(field string, details PurchaseAndSaleDetails) {
+	v.accountCode(field+".AccountCode", details.AccountCode)
+	v.accountCode(field+".COGSAccountCode", details.COGSAccountCode)
+	v.taxType(field+".TaxType", details.TaxType)
+}
+
+// Validate checks the items for problems Xero would reject them for
+func (i *Items) Validate() error {
+	return i.ValidateWithOptions(nil)
+}
+
+// ValidateWithOptions checks the items, optionally against the reference data of the organisation
+func (i *Items) ValidateWithOptions(options *ValidateOptions) error {
+	v := options.validator()
+	for n, item := range i.Items {
+		path := fmt.Sprintf("Items[%d]", n)
+		v.required(path+".Code", item.Code)
+		v.maxLength(path+".Code", item.Code, 30)
+		v.maxLength(path+".Name", item.Name, 50)
+		v.maxLength(path+".Description", item.Description, 4000)
+		v.maxLength(path+".PurchaseDescription", item.PurchaseDescription, 4000)
+		v.accountCode(path+".InventoryAssetAccountCode", item.InventoryAssetAccountCode)
+		v.details(path+".PurchaseDetails", item.PurchaseDetails)
+		v.details(path+".SalesDetails", item.SalesDetails)
+	}
+	return v.err()
+}
@@ -0,0 +1,132 @@
+package accounting
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func validationFields(err error) []string {
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		return nil
+	}
+	fields := []string{}
+	for _, e := range errs {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+func Test_InvoicesValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	valid := &Invoices{Invoices: []Invoice{{
+		Type:    "ACCREC",
+		Contact: Contact{Name: "City Limousines"},
+		LineItems: []LineItem{
+			{Description: "Consulting", AccountCode: "200", DiscountRate: decimal.NewFromInt(10)},
+			{ItemCode: "GB1-White", Quantity: decimal.NewFromInt(2)},
+		},
+	}}}
+	a.NoError(valid.Validate())
+
+	invoices := &Invoices{Invoices: []Invoice{{
+		Type:      "ACCPAY",
+		Reference: strings.Repeat("x", 256),
+		LineItems: []LineItem{
+			{Description: "Parts", DiscountRate: decimal.NewFromInt(5)},
+			{Tracking: []TrackingCategory{{Name: "Region"}, {Name: "Team"}, {Name: "Project"}}},
+		},
+	}}}
+	err := invoices.Validate()
+	a.Error(err)
+	a.Equal([]string{
+		"Invoices[0].Contact",
+		"Invoices[0].Reference",
+		"Invoices[0].LineItems[0].DiscountRate",
+		"Invoices[0].LineItems[1].Description",
+		"Invoices[0].LineItems[1].Tracking",
+	}, validationFields(err))
+	a.Len(err.(ValidationErrors).Field("Invoices[0].Reference"), 1)
+
+	//updates do not need the contact
+	update := &Invoices{Invoices: []Invoice{{Type: "ACCREC", InvoiceID: "8a1c0f1e-0000-0000-0000-000000000000"}}}
+	a.NoError(update.Validate())
+}
+
+func Test_ValidateWithReferenceData(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	reference := NewReferenceData(
+		[]Account{{Code: "200", Status: "ACTIVE"}, {Code: "090", Status: "ACTIVE"}, {Code: "300", Status: "ARCHIVED"}},
+		[]TaxRate{{TaxType: "OUTPUT", Status: "ACTIVE"}, {TaxType: "OLDTAX", Status: "DELETED"}},
+	)
+	options := &ValidateOptions{Reference: reference}
+
+	bankTransactions := &BankTransactions{BankTransactions: []BankTransaction{{
+		Type:        "RECEIVE",
+		Contact:     Contact{ContactID: "b6d2a4c0-0000-0000-0000-000000000000"},
+		BankAccount: BankAccount{Code: "090"},
+		LineItems: []LineItem{
+			{Description: "Sale", AccountCode: "200", TaxType: "OUTPUT"},
+			{Description: "Old sale", AccountCode: "300", TaxType: "OLDTAX"},
+		},
+	}}}
+	a.NoError(bankTransactions.Validate())
+	a.Equal([]string{
+		"BankTransactions[0].LineItems[1].AccountCode",
+		"BankTransactions[0].LineItems[1].TaxType",
+	}, validationFields(bankTransactions.ValidateWithOptions(options)))
+}
+
+func Test_ManualJournalsValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	manualJournals := &ManualJournals{ManualJournals: []ManualJournal{{
+		Narration: "Accrual",
+		JournalLines: []ManualJournalLine{
+			{AccountCode: "400", LineAmount: decimal.NewFromInt(100)},
+			{AccountCode: "800", LineAmount: decimal.NewFromInt(-100)},
+		},
+	}}}
+	a.NoError(manualJournals.Validate())
+
+	manualJournals.ManualJournals[0].Narration = ""
+	manualJournals.ManualJournals[0].JournalLines[1].AccountCode = ""
+	manualJournals.ManualJournals[0].JournalLines[1].LineAmount = decimal.NewFromInt(-90)
+	err := manualJournals.Validate()
+	a.Equal([]string{
+		"ManualJournals[0].Narration",
+		"ManualJournals[0].JournalLines[1].AccountCode",
+		"ManualJournals[0].JournalLines",
+	}, validationFields(err))
+	a.Contains(err.Error(), "debits and credits differ by 10.00")
+}
+
+func Test_ContactsAndItemsValidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	contacts := &Contacts{Contacts: []Contact{{
+		ContactNumber: strings.Repeat("1", 51),
+		Addresses:     &[]Address{{AddressType: "POBOX", PostalCode: strings.Repeat("9", 51)}},
+	}}}
+	a.Equal([]string{
+		"Contacts[0].Name",
+		"Contacts[0].ContactNumber",
+		"Contacts[0].Addresses[0].PostalCode",
+	}, validationFields(contacts.Validate()))
+
+	items := &Items{Items: []Item{{Name: strings.Repeat("n", 51), SalesDetails: PurchaseAndSaleDetails{AccountCode: "999"}}}}
+	a.Equal([]string{"Items[0].Code", "Items[0].Name"}, validationFields(items.Validate()))
+	a.Equal([]string{"Items[0].Code", "Items[0].Name", "Items[0].SalesDetails.AccountCode"}, validationFields(items.ValidateWithOptions(&ValidateOptions{
+		Reference: NewReferenceData([]Account{{Code: "200"}}, nil),
+	})))
+}
@@ -0,0 +1,209 @@
+package xerogolang
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omniboost/xerogolang/helpers"
+)
+
+const (
+	// DateFormat is how dates are written to Xero
+	DateFormat = "2006-01-02"
+
+	// DateTimeFormat is how times are written to Xero. Times have no offset and are in UTC
+	DateTimeFormat = "2006-01-02T15:04:05"
+)
+
+// parseTime reads any of the formats Xero returns - /Date(1494201600000+1300)/, 2017-05-08, 2017-05-08T00:00:00
+// with or without fractional seconds, and RFC3339. Times without an offset are in UTC
+func parseTime(value string) (time.Time, error) {
+	if strings.HasPrefix(value, "/Date(") {
+		return helpers.ParseDotNetJSONTime(value)
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", DateFormat} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a Xero date", value)
+}
+
+// unquote reads a JSON string, null and "" are read as an empty value
+func unquote(data []byte) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", fmt.Errorf("a date must be a string, not %s", string(data))
+	}
+	return value, nil
+}
+
+// Date is a calendar date such as the date of an invoice. It is held as midnight UTC so dates compare
+// with Equal and Before regardless of where they came from. The zero Date is an unset date
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of year, month and day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns the calendar date of t in the location of t
+func DateOf(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+	return NewDate(t.Year(), t.Month(), t.Day())
+}
+
+// Today returns the current date in the local time zone
+func Today() Date {
+	return DateOf(time.Now())
+}
+
+// ParseDate reads a date in any format Xero returns. Times are cut to the date in the offset they were
+// returned with, so /Date(1518609600000+1300)/ is 15 February 2018
+func ParseDate(value string) (Date, error) {
+	if value == "" {
+		return Date{}, nil
+	}
+	t, err := parseTime(value)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// AddDays returns the date days later, or earlier for negative days
+func (d Date) AddDays(days int) Date {
+	return Date{d.Time.AddDate(0, 0, days)}
+}
+
+// DaysUntil returns the number of days from d to other, negative when other is before d
+func (d Date) DaysUntil(other Date) int {
+	return int(other.Time.Sub(d.Time).Hours() / 24)
+}
+
+// String returns the date as YYYY-MM-DD, or an empty string for the zero Date
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateFormat)
+}
+
+// MarshalText writes the date as YYYY-MM-DD
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads a date in any format Xero returns
+func (d *Date) UnmarshalText(data []byte) error {
+	date, err := ParseDate(string(data))
+	*d = date
+	return err
+}
+
+// MarshalJSON writes the date as YYYY-MM-DD and the zero Date as null
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a date in any format Xero returns
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(value))
+}
+
+// MarshalXML leaves the zero Date out of the document
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.IsZero() {
+		return nil
+	}
+	return e.EncodeElement(d.String(), start)
+}
+
+// DateTime is a point in time such as UpdatedDateUTC. The zero DateTime is an unset time
+type DateTime struct {
+	time.Time
+}
+
+// NewDateTime returns the DateTime of t
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{t}
+}
+
+// ParseDateTime reads a time in any format Xero returns. The offset of /Date()/ times is kept as their location
+func ParseDateTime(value string) (DateTime, error) {
+	if value == "" {
+		return DateTime{}, nil
+	}
+	t, err := parseTime(value)
+	if err != nil {
+		return DateTime{}, err
+	}
+	return DateTime{t}, nil
+}
+
+// Date returns the calendar date of the time in its location
+func (d DateTime) Date() Date {
+	return DateOf(d.Time)
+}
+
+// String returns the time in UTC as YYYY-MM-DDTHH:MM:SS, or an empty string for the zero DateTime
+func (d DateTime) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.UTC().Format(DateTimeFormat)
+}
+
+// MarshalText writes the time in UTC as YYYY-MM-DDTHH:MM:SS
+func (d DateTime) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads a time in any format Xero returns
+func (d *DateTime) UnmarshalText(data []byte) error {
+	dateTime, err := ParseDateTime(string(data))
+	*d = dateTime
+	return err
+}
+
+// MarshalJSON writes the time in UTC as YYYY-MM-DDTHH:MM:SS and the zero DateTime as null
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a time in any format Xero returns
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(value))
+}
+
+// MarshalXML leaves the zero DateTime out of the document
+func (d DateTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if d.IsZero() {
+		return nil
+	}
+	return e.EncodeElement(d.String(), start)
+}
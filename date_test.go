@@ -0,0 +1,86 @@
+package xerogolang
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseDate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	for _, value := range []string{"/Date(1518652800000+0000)/", "/Date(1518609600000+1300)/", "/Date(1518652800000)/",
+		"2018-02-15", "2018-02-15T00:00:00", "2018-02-15T10:30:00.123", "2018-02-15T10:30:00+13:00"} {
+		date, err := ParseDate(value)
+		a.NoError(err, value)
+		a.Equal(NewDate(2018, time.February, 15), date, value)
+	}
+
+	date, err := ParseDate("")
+	a.NoError(err)
+	a.True(date.IsZero())
+
+	_, err = ParseDate("15 February 2018")
+	a.Error(err)
+
+	a.Equal(3, NewDate(2024, time.February, 28).DaysUntil(NewDate(2024, time.March, 2)))
+	a.Equal(NewDate(2024, time.March, 1), NewDate(2024, time.February, 28).AddDays(2))
+}
+
+func Test_ParseDateTime(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	dateTime, err := ParseDateTime("/Date(1494201600000+1300)/")
+	a.NoError(err)
+	a.True(dateTime.Equal(time.Date(2017, time.May, 8, 0, 0, 0, 0, time.UTC)))
+	a.Equal("2017-05-08T00:00:00", dateTime.String())
+	a.Equal(NewDate(2017, time.May, 8), dateTime.Date())
+
+	dateTime, err = ParseDateTime("/Date(1494201600000-0530)/")
+	a.NoError(err)
+	a.Equal(NewDate(2017, time.May, 7), dateTime.Date())
+
+	converted, err := helpers.DotNetJSONTimeToRFC3339("/Date(1494201600000+1300)/", true)
+	a.NoError(err)
+	a.Equal("2017-05-08T00:00:00Z", converted)
+	converted, err = helpers.DotNetJSONTimeToRFC3339("/Date(1494201600000+1300)/", false)
+	a.NoError(err)
+	a.Equal("2017-05-08T13:00:00", converted)
+}
+
+func Test_DateEncoding(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	type document struct {
+		Date           Date     `json:"Date,omitzero" xml:"Date,omitempty"`
+		DueDate        Date     `json:"DueDate" xml:"DueDate,omitempty"`
+		UpdatedDateUTC DateTime `json:"UpdatedDateUTC,omitzero" xml:"UpdatedDateUTC,omitempty"`
+	}
+
+	body, err := json.Marshal(document{Date: NewDate(2024, time.March, 1), UpdatedDateUTC: NewDateTime(time.Date(2024, time.March, 1, 23, 0, 0, 0, time.FixedZone("", -3600)))})
+	a.NoError(err)
+	a.Equal(`{"Date":"2024-03-01","DueDate":null,"UpdatedDateUTC":"2024-03-02T00:00:00"}`, string(body))
+
+	body, err = xml.Marshal(document{Date: NewDate(2024, time.March, 1)})
+	a.NoError(err)
+	a.Equal(`<document><Date>2024-03-01</Date></document>`, string(body))
+
+	read := document{}
+	a.NoError(json.Unmarshal([]byte(`{"Date":"/Date(1709251200000+0000)/","DueDate":null,"UpdatedDateUTC":"2024-03-02T00:00:00"}`), &read))
+	a.Equal(NewDate(2024, time.March, 1), read.Date)
+	a.True(read.DueDate.IsZero())
+	a.Equal("2024-03-02T00:00:00", read.UpdatedDateUTC.String())
+
+	read = document{}
+	a.NoError(xml.Unmarshal([]byte(`<document><Date>2024-03-01T00:00:00</Date></document>`), &read))
+	a.Equal(NewDate(2024, time.March, 1), read.Date)
+
+	a.EqualError(json.Unmarshal([]byte(`{"Date":20240301}`), &read), "a date must be a string, not 20240301")
+}
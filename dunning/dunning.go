@@ -0,0 +1,363 @@
+// Package dunning chases overdue sales invoices through configurable reminder stages.
+// Every reminder is recorded in the history of its invoice, which is also how the
+// stages already sent are found on the next run
+package dunning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/omniboost/xerogolang/query"
+	"github.com/shopspring/decimal"
+)
+
+// historyPrefix starts the history note of every reminder, followed by the stage name in brackets
+const historyPrefix = "Dunning reminder ("
+
+// defaultMessage is used for stages without a Message
+const defaultMessage = "{{.Stage.Name}} for invoice {{.Invoice.InvoiceNumber}}, {{.DaysOverdue}} days overdue with {{.AmountDue}} due"
+
+// Stage is a reminder sent once an invoice is a number of days overdue
+type Stage struct {
+	// Name identifies the stage in the invoice history - keep it stable between runs
+	Name string
+
+	// DaysOverdue is the number of days past the due date the stage starts
+	DaysOverdue int
+
+	// Message recorded in the invoice history. It is a text/template executed with the Reminder
+	Message string
+}
+
+// Options control a dunning run
+type Options struct {
+	// Stages in the order they are sent - their DaysOverdue must increase
+	Stages []Stage
+
+	// Date overdue days are counted to - defaults to today
+	AsOf time.Time
+
+	// Only chase these contacts - all contacts when empty
+	ContactIDs []string
+
+	// Invoices with less than this amount due are not chased
+	MinimumAmountDue decimal.Decimal
+
+	// Report the reminders that are due without recording them
+	DryRun bool
+}
+
+// Reminder is a stage due to be sent for an invoice
+type Reminder struct {
+	Invoice accounting.Invoice
+	Stage   Stage
+
+	// StageIndex is the position of Stage in the options
+	StageIndex int
+
+	DaysOverdue int
+
+	// AmountDue formatted with the currency of the invoice
+	AmountDue string
+
+	// Details is the note recorded in the invoice history
+	Details string
+
+	// Recorded is set once the note was added to the invoice history
+	Recorded bool
+}
+
+// ContactReminders holds the reminders due for a single contact
+type ContactReminders struct {
+	ContactID string
+	Name      string
+
+	// Stage is the most advanced stage due for the contact
+	Stage     Stage
+	AmountDue decimal.Decimal
+	Reminders []Reminder
+}
+
+// Result is the outcome of a dunning run
+type Result struct {
+	AsOf   time.Time
+	DryRun bool
+
+	// Reminders due, in the order they were recorded
+	Reminders []Reminder
+
+	// Contacts with reminders ordered by name
+	Contacts []*ContactReminders
+}
+
+// ByStage returns the contacts due for each stage, keyed by stage name
+func (r *Result) ByStage() map[string][]*ContactReminders {
+	stages := map[string][]*ContactReminders{}
+	for _, contact := range r.Contacts {
+		stages[contact.Stage.Name] = append(stages[contact.Stage.Name], contact)
+	}
+	return stages
+}
+
+func (o Options) validate() error {
+	if len(o.Stages) == 0 {
+		return errors.New("at least one stage is needed")
+	}
+	names := map[string]bool{}
+	for n, stage := range o.Stages {
+		if stage.Name == "" || strings.Contains(stage.Name, ")") {
+			return fmt.Errorf("stage %d needs a name without a closing bracket", n+1)
+		}
+		if names[stage.Name] {
+			return fmt.Errorf("stage %s is used twice", stage.Name)
+		}
+		names[stage.Name] = true
+		if n > 0 && stage.DaysOverdue <= o.Stages[n-1].DaysOverdue {
+			return errors.New("the DaysOverdue of the stages must increase")
+		}
+	}
+	return nil
+}
+
+func (o Options) asOf() time.Time {
+	asOf := o.AsOf
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	return time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Run finds the overdue invoices of the organisation, works out which reminder stage each one is due for and,
+// unless DryRun is set, records the reminders in the invoice histories
+func Run(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options Options) (*Result, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	asOf := options.asOf()
+
+	invoices, err := FindOverdueInvoices(ctx, provider, session, asOf, options.ContactIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	//only invoices due for a stage need their history read
+	sent := map[string]int{}
+	for _, invoice := range invoices {
+		if days, err := daysOverdue(invoice, asOf); err != nil || days < options.Stages[0].DaysOverdue {
+			continue
+		}
+		history, err := accounting.FindHistoryAndNotes(ctx, provider, session, "Invoices", invoice.InvoiceID)
+		if err != nil {
+			return nil, err
+		}
+		sent[invoice.InvoiceID] = SentStage(history.HistoryRecords, options.Stages)
+	}
+
+	result, err := Plan(invoices, sent, options)
+	if err != nil {
+		return nil, err
+	}
+	if options.DryRun {
+		return result, nil
+	}
+
+	for n, reminder := range result.Reminders {
+		records := &accounting.HistoryRecords{
+			HistoryRecords: []accounting.HistoryRecord{{Details: reminder.Details}},
+		}
+		if _, err := records.Create(xerogolang.WithIdempotencyStep(ctx, "reminder:"+reminder.Invoice.InvoiceID), provider, session, "Invoices", reminder.Invoice.InvoiceID); err != nil {
+			return result, fmt.Errorf("invoice %s: %s", reminder.Invoice.InvoiceNumber, err.Error())
+		}
+		result.Reminders[n].Recorded = true
+	}
+	result.group()
+	return result, nil
+}
+
+// FindOverdueInvoices pages through the authorised sales invoices with an amount due that were due before asOf.
+// Pages cannot be combined with a ContactIDs filter that is split across requests, so the contacts are paged
+// through in chunks of accounting.MaxFilterValues
+func FindOverdueInvoices(ctx context.Context, provider xerogolang.IProvider, session goth.Session, asOf time.Time, contactIDs []string) ([]accounting.Invoice, error) {
+	where := query.And(
+		query.Invoice.Type.Eq("ACCREC"),
+		query.Invoice.DueDate.Lt(asOf),
+		query.Invoice.AmountDue.Gt(decimal.Zero),
+	)
+
+	chunks := [][]string{nil}
+	if len(contactIDs) > 0 {
+		chunks = [][]string{}
+		for start := 0; start < len(contactIDs); start += accounting.MaxFilterValues {
+			end := start + accounting.MaxFilterValues
+			if end > len(contactIDs) {
+				end = len(contactIDs)
+			}
+			chunks = append(chunks, contactIDs[start:end])
+		}
+	}
+
+	invoices := []accounting.Invoice{}
+	for _, chunk := range chunks {
+		for page := 1; ; page++ {
+			found, err := accounting.FindInvoicesWithOptions(ctx, provider, session, &accounting.InvoicesOptions{
+				ListOptions: accounting.ListOptions{Where: where, Page: page},
+				Statuses:    []string{"AUTHORISED"},
+				ContactIDs:  chunk,
+			})
+			if err != nil {
+				return nil, err
+			}
+			invoices = append(invoices, found.Invoices...)
+			if len(found.Invoices) < 100 {
+				break
+			}
+		}
+	}
+	return invoices, nil
+}
+
+// SentStage returns the index of the most advanced stage found in the history of an invoice, or -1 when none was sent
+func SentStage(records []accounting.HistoryRecord, stages []Stage) int {
+	sent := -1
+	for _, record := range records {
+		if !strings.HasPrefix(record.Details, historyPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(record.Details, historyPrefix)
+		if end := strings.Index(name, ")"); end >= 0 {
+			name = name[:end]
+		}
+		for n, stage := range stages {
+			if stage.Name == name && n > sent {
+				sent = n
+			}
+		}
+	}
+	return sent
+}
+
+// Plan works out the reminders due for invoices. sent holds the index of the stage last sent for each InvoiceID,
+// invoices that are not in it have not been sent a reminder yet. Only the most advanced stage due is sent, so
+// an invoice that skipped a stage between runs does not get several reminders at once
+func Plan(invoices []accounting.Invoice, sent map[string]int, options Options) (*Result, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	asOf := options.asOf()
+
+	messages := make([]*template.Template, len(options.Stages))
+	for n, stage := range options.Stages {
+		message := stage.Message
+		if message == "" {
+			message = defaultMessage
+		}
+		t, err := template.New(stage.Name).Parse(message)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %s", stage.Name, err.Error())
+		}
+		messages[n] = t
+	}
+
+	result := &Result{AsOf: asOf, DryRun: options.DryRun, Reminders: []Reminder{}, Contacts: []*ContactReminders{}}
+	for _, invoice := range invoices {
+		if !invoice.Type.IsReceivable() || invoice.Status != accounting.InvoiceStatusAuthorised || !invoice.AmountDue.IsPositive() {
+			continue
+		}
+		if invoice.AmountDue.LessThan(options.MinimumAmountDue) {
+			continue
+		}
+		if len(options.ContactIDs) > 0 && !contains(options.ContactIDs, invoice.Contact.ContactID) {
+			continue
+		}
+		days, err := daysOverdue(invoice, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		stage := -1
+		for n := range options.Stages {
+			if days >= options.Stages[n].DaysOverdue {
+				stage = n
+			}
+		}
+		last, ok := sent[invoice.InvoiceID]
+		if !ok {
+			last = -1
+		}
+		if stage <= last {
+			continue
+		}
+
+		reminder := Reminder{
+			Invoice:     invoice,
+			Stage:       options.Stages[stage],
+			StageIndex:  stage,
+			DaysOverdue: days,
+			AmountDue:   invoice.Money(invoice.AmountDue).String(),
+		}
+		message := &bytes.Buffer{}
+		if err := messages[stage].Execute(message, reminder); err != nil {
+			return nil, fmt.Errorf("stage %s: %s", reminder.Stage.Name, err.Error())
+		}
+		reminder.Details = historyPrefix + reminder.Stage.Name + "): " + message.String()
+		result.Reminders = append(result.Reminders, reminder)
+	}
+
+	result.group()
+	return result, nil
+}
+
+// group collects the reminders by contact
+func (r *Result) group() {
+	r.Contacts = []*ContactReminders{}
+	contacts := map[string]*ContactReminders{}
+	stages := map[string]int{}
+	for _, reminder := range r.Reminders {
+		contact, ok := contacts[reminder.Invoice.Contact.ContactID]
+		if !ok {
+			contact = &ContactReminders{
+				ContactID: reminder.Invoice.Contact.ContactID,
+				Name:      reminder.Invoice.Contact.Name,
+				Reminders: []Reminder{},
+			}
+			contacts[contact.ContactID] = contact
+			stages[contact.ContactID] = -1
+			r.Contacts = append(r.Contacts, contact)
+		}
+		contact.Reminders = append(contact.Reminders, reminder)
+		contact.AmountDue = contact.AmountDue.Add(reminder.Invoice.AmountDue)
+		if reminder.StageIndex > stages[contact.ContactID] {
+			stages[contact.ContactID] = reminder.StageIndex
+			contact.Stage = reminder.Stage
+		}
+	}
+	sort.SliceStable(r.Contacts, func(i, j int) bool {
+		return r.Contacts[i].Name < r.Contacts[j].Name
+	})
+}
+
+// daysOverdue counts the days between the due date of an invoice and asOf
+func daysOverdue(invoice accounting.Invoice, asOf time.Time) (int, error) {
+	if invoice.DueDate.IsZero() {
+		return 0, fmt.Errorf("invoice %s has no due date", invoice.InvoiceNumber)
+	}
+	return invoice.DueDate.DaysUntil(xerogolang.DateOf(asOf)), nil
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package dunning
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+var testStages = []Stage{
+	{Name: "Friendly", DaysOverdue: 1},
+	{Name: "Firm", DaysOverdue: 14, Message: "Second reminder for {{.Invoice.InvoiceNumber}} ({{.AmountDue}})"},
+	{Name: "Final", DaysOverdue: 30},
+}
+
+func testDate(value string) xerogolang.Date {
+	date, err := xerogolang.ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return date
+}
+
+func testInvoice(id string, contactID string, dueDate string, amountDue int64) accounting.Invoice {
+	return accounting.Invoice{
+		Type:          "ACCREC",
+		Status:        "AUTHORISED",
+		InvoiceID:     id,
+		InvoiceNumber: "INV-" + id,
+		Contact:       accounting.Contact{ContactID: contactID, Name: "Contact " + contactID},
+		DueDate:       testDate(dueDate),
+		CurrencyCode:  "NZD",
+		AmountDue:     decimal.NewFromInt(amountDue),
+	}
+}
+
+func Test_Plan(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	invoices := []accounting.Invoice{
+		testInvoice("1", "a", "2024-03-30T00:00:00", 100),
+		testInvoice("2", "a", "2024-03-10T00:00:00", 200),
+		testInvoice("3", "b", "2024-02-01T00:00:00", 300),
+		testInvoice("4", "b", "2024-04-05T00:00:00", 400),
+		testInvoice("5", "c", "2024-03-01T00:00:00", 5),
+	}
+	sent := map[string]int{"3": 2}
+	options := Options{
+		Stages:           testStages,
+		AsOf:             time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		MinimumAmountDue: decimal.NewFromInt(10),
+		DryRun:           true,
+	}
+
+	result, err := Plan(invoices, sent, options)
+	a.NoError(err)
+	a.Len(result.Reminders, 2)
+	a.Equal("Friendly", result.Reminders[0].Stage.Name)
+	a.Equal(1, result.Reminders[0].DaysOverdue)
+	a.Equal("Firm", result.Reminders[1].Stage.Name)
+	a.Equal("Dunning reminder (Firm): Second reminder for INV-2 (NZD 200.00)", result.Reminders[1].Details)
+
+	a.Len(result.Contacts, 1)
+	a.Equal("Firm", result.Contacts[0].Stage.Name)
+	a.True(decimal.NewFromInt(300).Equal(result.Contacts[0].AmountDue))
+	a.Len(result.ByStage()["Firm"], 1)
+
+	//amounts due are shown in the minor units of the currency
+	yen := testInvoice("6", "d", "2024-03-10T00:00:00", 1500)
+	yen.CurrencyCode = "JPY"
+	result, err = Plan([]accounting.Invoice{yen}, nil, options)
+	a.NoError(err)
+	a.Equal("Dunning reminder (Firm): Second reminder for INV-6 (JPY 1500)", result.Reminders[0].Details)
+
+	_, err = Plan(invoices, sent, Options{Stages: []Stage{{Name: "A", DaysOverdue: 5}, {Name: "B", DaysOverdue: 5}}})
+	a.Error(err)
+}
+
+func Test_SentStage(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	records := []accounting.HistoryRecord{
+		{Details: "INV-2 was sent"},
+		{Details: "Dunning reminder (Friendly): first"},
+		{Details: "Dunning reminder (Firm): second"},
+	}
+	a.Equal(1, SentStage(records, testStages))
+	a.Equal(-1, SentStage(records[:1], testStages))
+}
+
+// findProvider records the ContactIDs of each page requested and returns no invoices
+type findProvider struct {
+	contactIDs [][]string
+}
+
+func (p *findProvider) Find(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, querystringParameters map[string]string) ([]byte, error) {
+	p.contactIDs = append(p.contactIDs, strings.Split(querystringParameters["ContactIDs"], ","))
+	return []byte(`{"Invoices":[]}`), nil
+}
+
+func (p *findProvider) Create(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected PUT %s", endpoint)
+}
+
+func (p *findProvider) Update(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected POST %s", endpoint)
+}
+
+func (p *findProvider) Remove(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected DELETE %s", endpoint)
+}
+
+func Test_FindOverdueInvoices(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	contactIDs := []string{}
+	for n := 0; n < accounting.MaxFilterValues+1; n++ {
+		contactIDs = append(contactIDs, fmt.Sprintf("00000000-0000-0000-0000-%012d", n))
+	}
+	provider := &findProvider{}
+	invoices, err := FindOverdueInvoices(context.Background(), provider, nil, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), contactIDs)
+	a.NoError(err)
+	a.Empty(invoices)
+	a.Len(provider.contactIDs, 2)
+	a.Len(provider.contactIDs[0], accounting.MaxFilterValues)
+	a.Equal(contactIDs[accounting.MaxFilterValues:], provider.contactIDs[1])
+}
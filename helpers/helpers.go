@@ -23,24 +23,38 @@ func ReaderToString(reader io.ReadCloser) string {
 		return ""
 	}
 	newString := buf.String()
-	_, err = fmt.Printf(newString)
+	_, err = fmt.Print(newString)
 	if err != nil {
 		return ""
 	}
 	return newString
 }
 
-func getTimestampAndOffset(regex *regexp.Regexp, timeString string) (int64, int64, error) {
-	separatedValues := regex.Split(timeString, 2)
-	timestamp, err := strconv.ParseInt(separatedValues[0], 10, 64)
-	if err != nil {
-		return 0, 0, err
+var dotNetJSONTime = regexp.MustCompile(`^/Date\((-?[0-9]+)(([+-])([0-9]{2})([0-9]{2}))?\)/$`)
+
+// ParseDotNetJSONTime parses the .Net formatted time returned by the Xero API, which looks like
+// /Date(1494201600000+1300)/ - milliseconds since the Unix epoch followed by the offset of the organisation
+// as hours and minutes. The time is returned in a location with that offset
+func ParseDotNetJSONTime(jsonTime string) (time.Time, error) {
+	matches := dotNetJSONTime.FindStringSubmatch(jsonTime)
+	if matches == nil {
+		return time.Time{}, fmt.Errorf("%q is not a .Net JSON time", jsonTime)
 	}
-	offset, err := strconv.ParseInt(separatedValues[1], 10, 64)
+	milliseconds, err := strconv.ParseInt(matches[1], 10, 64)
 	if err != nil {
-		return 0, 0, err
+		return time.Time{}, err
+	}
+	t := time.UnixMilli(milliseconds).UTC()
+	if matches[2] == "" {
+		return t, nil
+	}
+	hours, _ := strconv.Atoi(matches[4])
+	minutes, _ := strconv.Atoi(matches[5])
+	offset := hours*3600 + minutes*60
+	if matches[3] == "-" {
+		offset = -offset
 	}
-	return timestamp, offset, nil
+	return t.In(time.FixedZone("", offset)), nil
 }
 
 // DotNetJSONTimeToRFC3339 Converts the .Net formatted time returned by the Xero API to a more readable format
@@ -49,50 +63,17 @@ func DotNetJSONTimeToRFC3339(jsonTime string, isUTC bool) (string, error) {
 	if jsonTime == "" {
 		return "", nil
 	}
-	//The format returned looks like: /Date(1494201600000+0000)/
-	//so first we need to strip out the unnecessary /'s, brackets, and letters
-	numbersAndPlusSymbol := regexp.MustCompile("[0-9+-]")
-	jsonSlice := numbersAndPlusSymbol.FindAllString(jsonTime, -1)
-	//Then we join the resulting array into a string
-	jsonTimeString := strings.Join(jsonSlice[:], "")
-	//if the offset (the bit after the Unix timestamp) is positive (signalled by a + symbol)
-	//then we need to add it to the timestamp and return the result
-	var golangTime time.Time
-	if strings.Contains(jsonTimeString, "+") {
-		plusSymbol := regexp.MustCompile("\\+")
-		timestamp, offset, err := getTimestampAndOffset(plusSymbol, jsonTimeString)
-		if err != nil {
-			return time.Now().Format(time.RFC3339), err
-		}
-		golangTime = time.Unix((timestamp/1000)+offset, 0)
-	} else
-	//if the offset (the bit after the Unix timestamp) is negative (signalled by a - symbol)
-	//then we need to subtract it from the timestamp and return the result
-	if strings.Contains(jsonTimeString, "-") {
-		minusSymbol := regexp.MustCompile("\\-")
-		timestamp, offset, err := getTimestampAndOffset(minusSymbol, jsonTimeString)
-		if err != nil {
-			return time.Now().Format(time.RFC3339), err
-		}
-		golangTime = time.Unix((timestamp/1000)-offset, 0)
-	} else {
-		//If there is no offset then we just return the converted timestamp
-		timestamp, err := strconv.ParseInt(jsonTimeString, 10, 64)
-		if err != nil {
-			return time.Now().Format(time.RFC3339), err
-		}
-		golangTime = time.Unix((timestamp / 1000), 0)
+	golangTime, err := ParseDotNetJSONTime(jsonTime)
+	if err != nil {
+		return time.Now().Format(time.RFC3339), err
 	}
-	formattedTime := golangTime.UTC().Format(time.RFC3339)
 	//The Xero API does not expect an offset. We either need to supply the local time
-	//or the UTC time. If we designate the time format as local golang will add the offset
-	//but if we designate it as UTC it adds a Z suffix. To satisfy the API requirements we
-	//will remove the Z from local times so they aren't seen as UTC times.
+	//or the UTC time. If we designate the time format as UTC it adds a Z suffix, local
+	//times are the time in the offset of the organisation without one
 	if isUTC {
-		return formattedTime, nil
-	} else {
-		return strings.TrimSuffix(formattedTime, "Z"), nil
+		return golangTime.UTC().Format(time.RFC3339), nil
 	}
+	return golangTime.Format("2006-01-02T15:04:05"), nil
 }
 
 // TodayRFC3339 returns an RFC3339 formatted date
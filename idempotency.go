@@ -0,0 +1,73 @@
+package xerogolang
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// IdempotencyKeyHeader is the header Xero uses to recognise a write it has already processed
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength is the longest Idempotency-Key Xero accepts
+const MaxIdempotencyKeyLength = 128
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that makes Create and Update send key as their Idempotency-Key.
+// Every write made with the context sends the same key, so use it for a single write and the same key
+// again when that write timed out and is tried again
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// WithIdempotencyStep returns a context for one step of an operation that makes several writes, e.g. the
+// allocation of a reversal. The step gets the key of ctx with the step added, e.g. invoice-42:allocation,
+// so trying the operation again with the same key sends the same key for each step. When ctx has no key
+// it is returned unchanged and every write gets a key of its own
+func WithIdempotencyStep(ctx context.Context, step string) context.Context {
+	key, ok := IdempotencyKey(ctx)
+	if !ok {
+		return ctx
+	}
+	return WithIdempotencyKey(ctx, key+":"+step)
+}
+
+// IdempotencyKey returns the key set on a context by WithIdempotencyKey
+func IdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey generates a random key
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	//format as a version 4 UUID
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// idempotentHeaders returns a copy of additionalHeaders with an Idempotency-Key. A key in the headers wins over
+// one set on the context, and a key is generated when neither has one. The key is chosen once per write so
+// retries of the request send the same key
+func idempotentHeaders(ctx context.Context, additionalHeaders map[string]string) (map[string]string, error) {
+	headers := make(map[string]string, len(additionalHeaders)+1)
+	for key, value := range additionalHeaders {
+		headers[key] = value
+	}
+	if headers[IdempotencyKeyHeader] == "" {
+		if key, ok := IdempotencyKey(ctx); ok {
+			headers[IdempotencyKeyHeader] = key
+		} else {
+			headers[IdempotencyKeyHeader] = NewIdempotencyKey()
+		}
+	}
+	if len(headers[IdempotencyKeyHeader]) > MaxIdempotencyKeyLength {
+		return nil, fmt.Errorf("the Idempotency-Key %q is longer than %d characters", headers[IdempotencyKeyHeader], MaxIdempotencyKeyLength)
+	}
+	return headers, nil
+}
@@ -0,0 +1,115 @@
+package xerogolang
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func Test_IdempotentHeaders(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	headers := map[string]string{"Accept": "application/json"}
+	generated, err := idempotentHeaders(context.Background(), headers)
+	a.NoError(err)
+	a.Len(generated[IdempotencyKeyHeader], 36)
+	a.NotContains(headers, IdempotencyKeyHeader)
+	again, err := idempotentHeaders(context.Background(), headers)
+	a.NoError(err)
+	a.NotEqual(generated[IdempotencyKeyHeader], again[IdempotencyKeyHeader])
+
+	ctx := WithIdempotencyKey(context.Background(), "invoice-42")
+	withKey, err := idempotentHeaders(ctx, headers)
+	a.NoError(err)
+	a.Equal("invoice-42", withKey[IdempotencyKeyHeader])
+	key, ok := IdempotencyKey(ctx)
+	a.True(ok)
+	a.Equal("invoice-42", key)
+
+	step, err := idempotentHeaders(WithIdempotencyStep(ctx, "allocation"), headers)
+	a.NoError(err)
+	a.Equal("invoice-42:allocation", step[IdempotencyKeyHeader])
+	_, ok = IdempotencyKey(WithIdempotencyStep(context.Background(), "allocation"))
+	a.False(ok)
+
+	headers[IdempotencyKeyHeader] = "header-key"
+	withHeader, err := idempotentHeaders(ctx, headers)
+	a.NoError(err)
+	a.Equal("header-key", withHeader[IdempotencyKeyHeader])
+
+	_, err = idempotentHeaders(WithIdempotencyKey(context.Background(), strings.Repeat("k", MaxIdempotencyKeyLength+1)), nil)
+	a.Error(err)
+	_, err = idempotentHeaders(WithIdempotencyKey(context.Background(), strings.Repeat("k", MaxIdempotencyKeyLength)), nil)
+	a.NoError(err)
+}
+
+func Test_Oauth2RetryKeepsIdempotencyKey(t *testing.T) {
+	a := assert.New(t)
+
+	keys := []string{}
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		keys = append(keys, r.Header.Values(IdempotencyKeyHeader)...)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"Invoices":[]}`))
+	}))
+	defer server.Close()
+
+	endpoint := endpointProfile
+	endpointProfile = server.URL + "/"
+	defer func() { endpointProfile = endpoint }()
+
+	provider := NewOauth2("id", "secret", &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour)})
+	provider.TenantID = "idempotency-test"
+	_, err := provider.Create(context.Background(), nil, "Invoices", nil, []byte(`{"Invoices":[{}]}`))
+	a.NoError(err)
+	a.Len(keys, 2)
+	a.Equal(keys[0], keys[1])
+	a.Equal([]string{`{"Invoices":[{}]}`, `{"Invoices":[{}]}`}, bodies)
+}
+
+func Test_IdempotencyKeyKeptAcrossWrites(t *testing.T) {
+	a := assert.New(t)
+
+	keys := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	endpoint := endpointProfile
+	endpointProfile = server.URL + "/"
+	defer func() { endpointProfile = endpoint }()
+
+	provider := NewOauth2("id", "secret", &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour)})
+	provider.TenantID = "idempotency-writes-test"
+	//a write tried again on the same context sends the same key
+	ctx := WithIdempotencyKey(context.Background(), "invoice-42")
+	_, err := provider.Create(ctx, nil, "Invoices", nil, []byte(`{}`))
+	a.NoError(err)
+	_, err = provider.Create(ctx, nil, "Invoices", nil, []byte(`{}`))
+	a.NoError(err)
+	//the steps of an operation derive keys of their own
+	_, err = provider.Update(WithIdempotencyStep(ctx, "allocation"), nil, "CreditNotes/cn-1/Allocations", nil, []byte(`{}`))
+	a.NoError(err)
+	a.Equal([]string{"invoice-42", "invoice-42", "invoice-42:allocation"}, keys)
+
+	_, err = provider.Create(WithIdempotencyKey(ctx, strings.Repeat("k", MaxIdempotencyKeyLength+1)), nil, "Invoices", nil, []byte(`{}`))
+	a.Error(err)
+	a.Len(keys, 3)
+}
@@ -0,0 +1,180 @@
+package xerogolang
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// minorUnits are the ISO 4217 currencies that do not have 2 decimal places
+var minorUnits = map[string]int32{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits returns the number of decimal places of an ISO 4217 currency - 2 unless the currency has another
+func MinorUnits(currencyCode string) int32 {
+	if places, ok := minorUnits[strings.ToUpper(currencyCode)]; ok {
+		return places
+	}
+	return 2
+}
+
+// CurrencyMismatchError is returned when amounts in different currencies are added, subtracted or compared
+type CurrencyMismatchError struct {
+	Operation string
+	A         string
+	B         string
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("cannot %s %s and %s amounts", e.Operation, e.A, e.B)
+}
+
+// Money is an amount in an ISO 4217 currency. Amounts keep their full precision until Round is called,
+// so sums of rounded amounts are not rounded twice
+type Money struct {
+	amount       decimal.Decimal
+	currencyCode string
+}
+
+// NewMoney returns amount in the currency with code currencyCode e.g. NZD
+func NewMoney(amount decimal.Decimal, currencyCode string) Money {
+	return Money{amount: amount, currencyCode: strings.ToUpper(currencyCode)}
+}
+
+// ZeroMoney returns no money in a currency, to start a sum
+func ZeroMoney(currencyCode string) Money {
+	return NewMoney(decimal.Zero, currencyCode)
+}
+
+// Amount returns the amount without its currency
+func (m Money) Amount() decimal.Decimal {
+	return m.amount
+}
+
+// CurrencyCode returns the ISO 4217 code of the currency
+func (m Money) CurrencyCode() string {
+	return m.currencyCode
+}
+
+// Round rounds the amount to the minor units of its currency, e.g. cents for USD and whole yen for JPY
+func (m Money) Round() Money {
+	return Money{amount: m.amount.Round(MinorUnits(m.currencyCode)), currencyCode: m.currencyCode}
+}
+
+func (m Money) sameCurrency(operation string, other Money) error {
+	if m.currencyCode != other.currencyCode {
+		return &CurrencyMismatchError{Operation: operation, A: m.currencyCode, B: other.currencyCode}
+	}
+	return nil
+}
+
+// Add returns m + other. It fails when other is in another currency
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency("add", other); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Add(other.amount), currencyCode: m.currencyCode}, nil
+}
+
+// Sub returns m - other. It fails when other is in another currency
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency("subtract", other); err != nil {
+		return Money{}, err
+	}
+	return Money{amount: m.amount.Sub(other.amount), currencyCode: m.currencyCode}, nil
+}
+
+// Cmp compares m and other, returning -1, 0 or 1. It fails when other is in another currency
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency("compare", other); err != nil {
+		return 0, err
+	}
+	return m.amount.Cmp(other.amount), nil
+}
+
+// Mul returns m multiplied by a quantity or rate
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{amount: m.amount.Mul(factor), currencyCode: m.currencyCode}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{amount: m.amount.Neg(), currencyCode: m.currencyCode}
+}
+
+// Abs returns m without its sign
+func (m Money) Abs() Money {
+	return Money{amount: m.amount.Abs(), currencyCode: m.currencyCode}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.amount.IsZero()
+}
+
+// IsPositive reports whether the amount is more than zero
+func (m Money) IsPositive() bool {
+	return m.amount.IsPositive()
+}
+
+// IsNegative reports whether the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.amount.IsNegative()
+}
+
+// Convert returns the amount in another currency. Rate is the units of m's currency per unit of the other,
+// as Xero sets the CurrencyRate of a document: units of the document currency per unit of the base currency.
+// The result is rounded to the minor units of the other currency
+func (m Money) Convert(currencyCode string, rate decimal.Decimal) (Money, error) {
+	currencyCode = strings.ToUpper(currencyCode)
+	if currencyCode == m.currencyCode {
+		return m, nil
+	}
+	if !rate.IsPositive() {
+		return Money{}, fmt.Errorf("cannot convert %s to %s at a rate of %s", m.currencyCode, currencyCode, rate.String())
+	}
+	return NewMoney(m.amount.Div(rate), currencyCode).Round(), nil
+}
+
+// String returns the currency code and the amount rounded to the minor units of the currency, e.g. JPY 1235
+func (m Money) String() string {
+	return strings.TrimSpace(m.currencyCode + " " + m.amount.StringFixed(MinorUnits(m.currencyCode)))
+}
+
+type moneyJSON struct {
+	Amount       decimal.Decimal `json:"Amount"`
+	CurrencyCode string          `json:"CurrencyCode"`
+}
+
+// MarshalJSON writes the money as {"Amount":"12.5","CurrencyCode":"NZD"}
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.amount, CurrencyCode: m.currencyCode})
+}
+
+// UnmarshalJSON reads money written by MarshalJSON
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value moneyJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*m = NewMoney(value.Amount, value.CurrencyCode)
+	return nil
+}
+
+// SumMoney adds up amounts in a currency. It fails when any amount is in another currency
+func SumMoney(currencyCode string, amounts ...Money) (Money, error) {
+	sum := ZeroMoney(currencyCode)
+	for _, amount := range amounts {
+		var err error
+		if sum, err = sum.Add(amount); err != nil {
+			return Money{}, err
+		}
+	}
+	return sum, nil
+}
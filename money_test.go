@@ -0,0 +1,78 @@
+package xerogolang
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Money(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(int32(0), MinorUnits("JPY"))
+	a.Equal(int32(3), MinorUnits("kwd"))
+	a.Equal(int32(2), MinorUnits("NZD"))
+
+	yen := NewMoney(decimal.RequireFromString("1234.5"), "jpy")
+	a.Equal("JPY", yen.CurrencyCode())
+	a.Equal("JPY 1235", yen.String())
+	a.Equal("1235", yen.Round().Amount().String())
+	a.Equal("NZD 10.10", NewMoney(decimal.RequireFromString("10.1"), "NZD").String())
+
+	sum, err := SumMoney("NZD", NewMoney(decimal.RequireFromString("10.005"), "NZD"), NewMoney(decimal.RequireFromString("0.005"), "NZD"))
+	a.NoError(err)
+	a.Equal("10.01", sum.Round().Amount().String())
+
+	_, err = sum.Add(yen)
+	a.EqualError(err, "cannot add NZD and JPY amounts")
+	mismatch, ok := err.(*CurrencyMismatchError)
+	a.True(ok)
+	a.Equal("JPY", mismatch.B)
+	_, err = sum.Cmp(yen)
+	a.Error(err)
+
+	difference, err := sum.Sub(NewMoney(decimal.NewFromInt(20), "NZD"))
+	a.NoError(err)
+	a.True(difference.IsNegative())
+	a.True(difference.Abs().IsPositive())
+	a.Equal("NZD 30.00", NewMoney(decimal.NewFromInt(10), "NZD").Mul(decimal.NewFromInt(3)).String())
+}
+
+func Test_MoneyConvert(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	//a USD document in a NZD organisation, 0.6 USD to the NZD
+	usd := NewMoney(decimal.NewFromInt(100), "USD")
+	nzd, err := usd.Convert("NZD", decimal.RequireFromString("0.6"))
+	a.NoError(err)
+	a.Equal("NZD 166.67", nzd.String())
+	a.Equal("166.67", nzd.Amount().String())
+
+	yen, err := usd.Convert("JPY", decimal.RequireFromString("0.0067"))
+	a.NoError(err)
+	a.Equal("14925", yen.Amount().String())
+
+	same, err := usd.Convert("usd", decimal.Zero)
+	a.NoError(err)
+	a.Equal(usd, same)
+
+	_, err = usd.Convert("NZD", decimal.Zero)
+	a.EqualError(err, "cannot convert USD to NZD at a rate of 0")
+}
+
+func Test_MoneyJSON(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	body, err := json.Marshal(NewMoney(decimal.RequireFromString("12.5"), "NZD"))
+	a.NoError(err)
+	a.Equal(`{"Amount":"12.5","CurrencyCode":"NZD"}`, string(body))
+
+	var money Money
+	a.NoError(json.Unmarshal(body, &money))
+	a.Equal("NZD 12.50", money.String())
+}
@@ -93,7 +93,11 @@ func (p *Oauth2Provider) Create(ctx context.Context, session goth.Session, endpo
 		return nil, err
 	}
 
-	return p.processRequest(request, session, additionalHeaders)
+	headers, err := idempotentHeaders(ctx, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return p.processRequest(request, session, headers)
 }
 
 // Update sends data to an endpoint and returns a response to be unmarshaled into the appropriate data type
@@ -105,7 +109,11 @@ func (p *Oauth2Provider) Update(ctx context.Context, session goth.Session, endpo
 		return nil, err
 	}
 
-	return p.processRequest(request, session, additionalHeaders)
+	headers, err := idempotentHeaders(ctx, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return p.processRequest(request, session, headers)
 }
 
 // Remove deletes the specified data from an endpoint
@@ -134,10 +142,10 @@ func (p *Oauth2Provider) Debug(debug bool) {
 
 // processRequest processes a request prior to it being sent to the API
 func (p *Oauth2Provider) processRequest(request *http.Request, session goth.Session, additionalHeaders map[string]string) ([]byte, error) {
-	request.Header.Add("User-Agent", p.UserAgentString)
-	request.Header.Add("Xero-tenant-id", p.TenantID)
+	request.Header.Set("User-Agent", p.UserAgentString)
+	request.Header.Set("Xero-tenant-id", p.TenantID)
 	for key, value := range additionalHeaders {
-		request.Header.Add(key, value)
+		request.Header.Set(key, value)
 	}
 
 	if p.debug {
@@ -173,8 +181,16 @@ func (p *Oauth2Provider) processRequest(request *http.Request, session goth.Sess
 	}
 
 	// Handle '429 - Too many requests' response
+	// The retry sends the same headers, including the Idempotency-Key, and a fresh copy of the body
 	if response != nil && response.StatusCode == 429 {
+		response.Body.Close()
 		p.sleepUntilRetryAfter(response)
+		if request.GetBody != nil {
+			request.Body, err = request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
 		return p.processRequest(request, session, additionalHeaders)
 	}
 
@@ -185,9 +201,7 @@ func (p *Oauth2Provider) processRequest(request *http.Request, session goth.Sess
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			helpers.ReaderToString(response.Body),
-		)
+		return nil, fmt.Errorf("%s", helpers.ReaderToString(response.Body))
 	}
 
 	responseBytes, err := io.ReadAll(response.Body)
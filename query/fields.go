@@ -0,0 +1,209 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Field is the name of an element that can be used in where and order clauses e.g. Contact.Name
+type Field string
+
+// Asc orders results by the field in ascending order
+func (f Field) Asc() Order {
+	return Order{field: string(f)}
+}
+
+// Desc orders results by the field in descending order
+func (f Field) Desc() Order {
+	return Order{field: string(f), descending: true}
+}
+
+// IsNull matches records where the field has no value
+func (f Field) IsNull() Condition {
+	return f.compare("==", "null")
+}
+
+// IsNotNull matches records where the field has a value
+func (f Field) IsNotNull() Condition {
+	return f.compare("!=", "null")
+}
+
+func (f Field) compare(operator string, literal string) Condition {
+	return Condition{expr: string(f) + operator + literal}
+}
+
+func (f Field) call(method string, literal string) Condition {
+	return Condition{expr: string(f) + "." + method + "(" + literal + ")"}
+}
+
+// StringField is a field holding text
+type StringField struct{ Field }
+
+// Eq matches records where the field equals value
+func (f StringField) Eq(value string) Condition {
+	return f.compare("==", String(value))
+}
+
+// NotEq matches records where the field does not equal value
+func (f StringField) NotEq(value string) Condition {
+	return f.compare("!=", String(value))
+}
+
+// In matches records where the field equals any of values
+func (f StringField) In(values ...string) Condition {
+	conditions := make([]Condition, len(values))
+	for n, value := range values {
+		conditions[n] = f.Eq(value)
+	}
+	return Or(conditions...)
+}
+
+// Contains matches records where the field contains value
+func (f StringField) Contains(value string) Condition {
+	return f.call("Contains", String(value))
+}
+
+// StartsWith matches records where the field starts with value
+func (f StringField) StartsWith(value string) Condition {
+	return f.call("StartsWith", String(value))
+}
+
+// EndsWith matches records where the field ends with value
+func (f StringField) EndsWith(value string) Condition {
+	return f.call("EndsWith", String(value))
+}
+
+// GUIDField is a field holding a Xero identifier
+type GUIDField struct{ Field }
+
+// Eq matches records where the field equals id
+func (f GUIDField) Eq(id string) Condition {
+	return f.compare("==", Guid(id))
+}
+
+// NotEq matches records where the field does not equal id
+func (f GUIDField) NotEq(id string) Condition {
+	return f.compare("!=", Guid(id))
+}
+
+// In matches records where the field equals any of ids
+func (f GUIDField) In(ids ...string) Condition {
+	conditions := make([]Condition, len(ids))
+	for n, id := range ids {
+		conditions[n] = f.Eq(id)
+	}
+	return Or(conditions...)
+}
+
+// DateField is a field holding a date or a date and time
+type DateField struct{ Field }
+
+// Eq matches records where the field equals t
+func (f DateField) Eq(t time.Time) Condition {
+	return f.compare("==", DateTime(t))
+}
+
+// NotEq matches records where the field does not equal t
+func (f DateField) NotEq(t time.Time) Condition {
+	return f.compare("!=", DateTime(t))
+}
+
+// Lt matches records where the field is before t
+func (f DateField) Lt(t time.Time) Condition {
+	return f.compare("<", DateTime(t))
+}
+
+// LtEq matches records where the field is on or before t
+func (f DateField) LtEq(t time.Time) Condition {
+	return f.compare("<=", DateTime(t))
+}
+
+// Gt matches records where the field is after t
+func (f DateField) Gt(t time.Time) Condition {
+	return f.compare(">", DateTime(t))
+}
+
+// GtEq matches records where the field is on or after t
+func (f DateField) GtEq(t time.Time) Condition {
+	return f.compare(">=", DateTime(t))
+}
+
+// Between matches records where the field is on or after from and on or before to
+func (f DateField) Between(from time.Time, to time.Time) Condition {
+	return And(f.GtEq(from), f.LtEq(to))
+}
+
+// DecimalField is a field holding an amount or a quantity
+type DecimalField struct{ Field }
+
+// Eq matches records where the field equals d
+func (f DecimalField) Eq(d decimal.Decimal) Condition {
+	return f.compare("==", d.String())
+}
+
+// NotEq matches records where the field does not equal d
+func (f DecimalField) NotEq(d decimal.Decimal) Condition {
+	return f.compare("!=", d.String())
+}
+
+// Lt matches records where the field is less than d
+func (f DecimalField) Lt(d decimal.Decimal) Condition {
+	return f.compare("<", d.String())
+}
+
+// LtEq matches records where the field is less than or equal to d
+func (f DecimalField) LtEq(d decimal.Decimal) Condition {
+	return f.compare("<=", d.String())
+}
+
+// Gt matches records where the field is greater than d
+func (f DecimalField) Gt(d decimal.Decimal) Condition {
+	return f.compare(">", d.String())
+}
+
+// GtEq matches records where the field is greater than or equal to d
+func (f DecimalField) GtEq(d decimal.Decimal) Condition {
+	return f.compare(">=", d.String())
+}
+
+// BoolField is a field holding true or false
+type BoolField struct{ Field }
+
+// Eq matches records where the field equals b
+func (f BoolField) Eq(b bool) Condition {
+	return f.compare("==", fmt.Sprintf("%t", b))
+}
+
+// IsTrue matches records where the field is true
+func (f BoolField) IsTrue() Condition {
+	return f.Eq(true)
+}
+
+// IsFalse matches records where the field is false
+func (f BoolField) IsFalse() Condition {
+	return f.Eq(false)
+}
+
+// String formats s as a quoted string literal, escaping backslashes and double quotes
+func String(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// Guid formats id as a Guid literal e.g. Guid("297c2dc5-cc47-4afd-8ec8-74990b8761e9")
+func Guid(id string) string {
+	return "Guid(" + String(id) + ")"
+}
+
+// DateTime formats t as a DateTime literal e.g. DateTime(2024,01,01).
+// The time of day is only included when it is set
+func DateTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("DateTime(2006,01,02)")
+	}
+	return t.Format("DateTime(2006,01,02,15,04,05)")
+}
@@ -0,0 +1,155 @@
+// Package query builds the where and order querystring parameters understood by the
+// Find functions in the accounting package, so expressions such as
+// Status=="AUTHORISED" AND Date>=DateTime(2024,01,01) don't have to be written by hand
+package query
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Condition is a boolean expression that can be used in a where clause
+type Condition struct {
+	expr string
+	//compound is true when expr joins several conditions and needs brackets when nested
+	compound bool
+}
+
+// String returns the condition formatted as a Xero where expression
+func (c Condition) String() string {
+	return c.expr
+}
+
+// IsZero reports whether the condition is empty
+func (c Condition) IsZero() bool {
+	return c.expr == ""
+}
+
+// And joins the condition with others using AND
+func (c Condition) And(others ...Condition) Condition {
+	return And(append([]Condition{c}, others...)...)
+}
+
+// Or joins the condition with others using OR
+func (c Condition) Or(others ...Condition) Condition {
+	return Or(append([]Condition{c}, others...)...)
+}
+
+// And joins conditions using AND - empty conditions are ignored
+func And(conditions ...Condition) Condition {
+	return join(" AND ", conditions)
+}
+
+// Or joins conditions using OR - empty conditions are ignored
+func Or(conditions ...Condition) Condition {
+	return join(" OR ", conditions)
+}
+
+// Not negates a condition
+func Not(c Condition) Condition {
+	if c.IsZero() {
+		return c
+	}
+	return Condition{expr: "!(" + c.expr + ")"}
+}
+
+func join(operator string, conditions []Condition) Condition {
+	nonEmpty := []Condition{}
+	for _, c := range conditions {
+		if !c.IsZero() {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return Condition{}
+	case 1:
+		return nonEmpty[0]
+	}
+
+	parts := make([]string, len(nonEmpty))
+	for n, c := range nonEmpty {
+		if c.compound {
+			parts[n] = "(" + c.expr + ")"
+		} else {
+			parts[n] = c.expr
+		}
+	}
+	return Condition{expr: strings.Join(parts, operator), compound: true}
+}
+
+// Order is a single ordering element of an order clause
+type Order struct {
+	field      string
+	descending bool
+}
+
+// String returns the ordering formatted as used in an order clause e.g. DueDate DESC
+func (o Order) String() string {
+	if o.descending {
+		return o.field + " DESC"
+	}
+	return o.field
+}
+
+// Query collects a where clause, an order clause and paging details
+type Query struct {
+	where Condition
+	order []Order
+	page  int
+}
+
+// New creates an empty Query
+func New() *Query {
+	return &Query{}
+}
+
+// Where creates a Query with the given conditions joined using AND
+func Where(conditions ...Condition) *Query {
+	return New().Where(conditions...)
+}
+
+// Where adds conditions to the query - they are joined with any existing conditions using AND
+func (q *Query) Where(conditions ...Condition) *Query {
+	q.where = And(append([]Condition{q.where}, conditions...)...)
+	return q
+}
+
+// OrderBy adds elements to the order clause of the query
+func (q *Query) OrderBy(order ...Order) *Query {
+	q.order = append(q.order, order...)
+	return q
+}
+
+// Page sets the page to request - Xero returns 100 records per page
+func (q *Query) Page(page int) *Query {
+	q.page = page
+	return q
+}
+
+// Params returns the querystringParameters expected by the Find functions in the accounting package
+func (q *Query) Params() map[string]string {
+	return q.MergeParams(nil)
+}
+
+// MergeParams adds the where, order and page parameters of the query to an existing
+// querystringParameters map and returns it. A new map is created when params is nil
+func (q *Query) MergeParams(params map[string]string) map[string]string {
+	if params == nil {
+		params = map[string]string{}
+	}
+	if !q.where.IsZero() {
+		params["where"] = q.where.String()
+	}
+	if len(q.order) > 0 {
+		order := make([]string, len(q.order))
+		for n, o := range q.order {
+			order[n] = o.String()
+		}
+		params["order"] = strings.Join(order, ",")
+	}
+	if q.page > 0 {
+		params["page"] = strconv.Itoa(q.page)
+	}
+	return params
+}
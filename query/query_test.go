@@ -0,0 +1,55 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Literals(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	a.Equal(`"Vanderlay \"Industries\" \\ Co"`, String(`Vanderlay "Industries" \ Co`))
+	a.Equal(`Guid("297c2dc5-cc47-4afd-8ec8-74990b8761e9")`, Guid("297c2dc5-cc47-4afd-8ec8-74990b8761e9"))
+	a.Equal("DateTime(2024,01,01)", DateTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	a.Equal("DateTime(2024,01,01,13,05,09)", DateTime(time.Date(2024, 1, 1, 13, 5, 9, 0, time.UTC)))
+}
+
+func Test_Conditions(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	c := Invoice.Status.Eq("AUTHORISED").And(Invoice.Date.GtEq(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	a.Equal(`Status=="AUTHORISED" AND Date>=DateTime(2024,01,01)`, c.String())
+
+	c = And(Invoice.Type.Eq("ACCREC"), Invoice.Status.In("DRAFT", "SUBMITTED"))
+	a.Equal(`Type=="ACCREC" AND (Status=="DRAFT" OR Status=="SUBMITTED")`, c.String())
+
+	c = Or(Invoice.Contact.Name.StartsWith("Van"), Not(Invoice.AmountDue.Gt(decimal.NewFromFloat(10.5))))
+	a.Equal(`Contact.Name.StartsWith("Van") OR !(AmountDue>10.5)`, c.String())
+
+	c = And(Condition{}, Invoice.Contact.ContactID.Eq("111-111"), Condition{})
+	a.Equal(`Contact.ContactID==Guid("111-111")`, c.String())
+}
+
+func Test_Params(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	params := Where(Contact.IsCustomer.IsTrue()).
+		Where(Contact.ContactStatus.Eq("ACTIVE")).
+		OrderBy(Contact.Name.Asc(), Contact.UpdatedDateUTC.Desc()).
+		Page(2).
+		Params()
+
+	a.Equal(map[string]string{
+		"where": `IsCustomer==true AND ContactStatus=="ACTIVE"`,
+		"order": "Name,UpdatedDateUTC DESC",
+		"page":  "2",
+	}, params)
+
+	a.Equal(map[string]string{}, New().Params())
+}
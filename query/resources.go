@@ -0,0 +1,306 @@
+package query
+
+// ContactFields are the filterable elements of a Contact
+type ContactFields struct {
+	ContactID      GUIDField
+	ContactNumber  StringField
+	AccountNumber  StringField
+	ContactStatus  StringField
+	Name           StringField
+	FirstName      StringField
+	LastName       StringField
+	EmailAddress   StringField
+	TaxNumber      StringField
+	IsSupplier     BoolField
+	IsCustomer     BoolField
+	UpdatedDateUTC DateField
+}
+
+// Contact references the elements of a Contact
+var Contact = contactFields("")
+
+func contactFields(prefix string) ContactFields {
+	return ContactFields{
+		ContactID:      GUIDField{Field(prefix + "ContactID")},
+		ContactNumber:  StringField{Field(prefix + "ContactNumber")},
+		AccountNumber:  StringField{Field(prefix + "AccountNumber")},
+		ContactStatus:  StringField{Field(prefix + "ContactStatus")},
+		Name:           StringField{Field(prefix + "Name")},
+		FirstName:      StringField{Field(prefix + "FirstName")},
+		LastName:       StringField{Field(prefix + "LastName")},
+		EmailAddress:   StringField{Field(prefix + "EmailAddress")},
+		TaxNumber:      StringField{Field(prefix + "TaxNumber")},
+		IsSupplier:     BoolField{Field(prefix + "IsSupplier")},
+		IsCustomer:     BoolField{Field(prefix + "IsCustomer")},
+		UpdatedDateUTC: DateField{Field(prefix + "UpdatedDateUTC")},
+	}
+}
+
+// InvoiceFields are the filterable elements of an Invoice
+type InvoiceFields struct {
+	InvoiceID      GUIDField
+	InvoiceNumber  StringField
+	Reference      StringField
+	Type           StringField
+	Status         StringField
+	Contact        ContactFields
+	Date           DateField
+	DueDate        DateField
+	CurrencyCode   StringField
+	SubTotal       DecimalField
+	TotalTax       DecimalField
+	Total          DecimalField
+	AmountDue      DecimalField
+	AmountPaid     DecimalField
+	AmountCredited DecimalField
+	SentToContact  BoolField
+	UpdatedDateUTC DateField
+}
+
+// Invoice references the elements of an Invoice
+var Invoice = InvoiceFields{
+	InvoiceID:      GUIDField{"InvoiceID"},
+	InvoiceNumber:  StringField{"InvoiceNumber"},
+	Reference:      StringField{"Reference"},
+	Type:           StringField{"Type"},
+	Status:         StringField{"Status"},
+	Contact:        contactFields("Contact."),
+	Date:           DateField{"Date"},
+	DueDate:        DateField{"DueDate"},
+	CurrencyCode:   StringField{"CurrencyCode"},
+	SubTotal:       DecimalField{"SubTotal"},
+	TotalTax:       DecimalField{"TotalTax"},
+	Total:          DecimalField{"Total"},
+	AmountDue:      DecimalField{"AmountDue"},
+	AmountPaid:     DecimalField{"AmountPaid"},
+	AmountCredited: DecimalField{"AmountCredited"},
+	SentToContact:  BoolField{"SentToContact"},
+	UpdatedDateUTC: DateField{"UpdatedDateUTC"},
+}
+
+// CreditNoteFields are the filterable elements of a CreditNote
+type CreditNoteFields struct {
+	CreditNoteID     GUIDField
+	CreditNoteNumber StringField
+	Reference        StringField
+	Type             StringField
+	Status           StringField
+	Contact          ContactFields
+	Date             DateField
+	CurrencyCode     StringField
+	SubTotal         DecimalField
+	TotalTax         DecimalField
+	Total            DecimalField
+	RemainingCredit  DecimalField
+	UpdatedDateUTC   DateField
+}
+
+// CreditNote references the elements of a CreditNote
+var CreditNote = CreditNoteFields{
+	CreditNoteID:     GUIDField{"CreditNoteID"},
+	CreditNoteNumber: StringField{"CreditNoteNumber"},
+	Reference:        StringField{"Reference"},
+	Type:             StringField{"Type"},
+	Status:           StringField{"Status"},
+	Contact:          contactFields("Contact."),
+	Date:             DateField{"Date"},
+	CurrencyCode:     StringField{"CurrencyCode"},
+	SubTotal:         DecimalField{"SubTotal"},
+	TotalTax:         DecimalField{"TotalTax"},
+	Total:            DecimalField{"Total"},
+	RemainingCredit:  DecimalField{"RemainingCredit"},
+	UpdatedDateUTC:   DateField{"UpdatedDateUTC"},
+}
+
+// PaymentFields are the filterable elements of a Payment
+type PaymentFields struct {
+	PaymentID      GUIDField
+	PaymentType    StringField
+	Status         StringField
+	Reference      StringField
+	Date           DateField
+	Amount         DecimalField
+	IsReconciled   BoolField
+	InvoiceID      GUIDField
+	CreditNoteID   GUIDField
+	AccountID      GUIDField
+	UpdatedDateUTC DateField
+}
+
+// Payment references the elements of a Payment
+var Payment = PaymentFields{
+	PaymentID:      GUIDField{"PaymentID"},
+	PaymentType:    StringField{"PaymentType"},
+	Status:         StringField{"Status"},
+	Reference:      StringField{"Reference"},
+	Date:           DateField{"Date"},
+	Amount:         DecimalField{"Amount"},
+	IsReconciled:   BoolField{"IsReconciled"},
+	InvoiceID:      GUIDField{"Invoice.InvoiceID"},
+	CreditNoteID:   GUIDField{"CreditNote.CreditNoteID"},
+	AccountID:      GUIDField{"Account.AccountID"},
+	UpdatedDateUTC: DateField{"UpdatedDateUTC"},
+}
+
+// BankTransactionFields are the filterable elements of a BankTransaction
+type BankTransactionFields struct {
+	BankTransactionID GUIDField
+	Type              StringField
+	Status            StringField
+	Reference         StringField
+	Contact           ContactFields
+	BankAccountID     GUIDField
+	BankAccountCode   StringField
+	Date              DateField
+	IsReconciled      BoolField
+	CurrencyCode      StringField
+	Total             DecimalField
+	UpdatedDateUTC    DateField
+}
+
+// BankTransaction references the elements of a BankTransaction
+var BankTransaction = BankTransactionFields{
+	BankTransactionID: GUIDField{"BankTransactionID"},
+	Type:              StringField{"Type"},
+	Status:            StringField{"Status"},
+	Reference:         StringField{"Reference"},
+	Contact:           contactFields("Contact."),
+	BankAccountID:     GUIDField{"BankAccount.AccountID"},
+	BankAccountCode:   StringField{"BankAccount.Code"},
+	Date:              DateField{"Date"},
+	IsReconciled:      BoolField{"IsReconciled"},
+	CurrencyCode:      StringField{"CurrencyCode"},
+	Total:             DecimalField{"Total"},
+	UpdatedDateUTC:    DateField{"UpdatedDateUTC"},
+}
+
+// AccountFields are the filterable elements of an Account
+type AccountFields struct {
+	AccountID               GUIDField
+	Code                    StringField
+	Name                    StringField
+	Type                    StringField
+	Class                   StringField
+	Status                  StringField
+	TaxType                 StringField
+	SystemAccount           StringField
+	EnablePaymentsToAccount BoolField
+	UpdatedDateUTC          DateField
+}
+
+// Account references the elements of an Account
+var Account = AccountFields{
+	AccountID:               GUIDField{"AccountID"},
+	Code:                    StringField{"Code"},
+	Name:                    StringField{"Name"},
+	Type:                    StringField{"Type"},
+	Class:                   StringField{"Class"},
+	Status:                  StringField{"Status"},
+	TaxType:                 StringField{"TaxType"},
+	SystemAccount:           StringField{"SystemAccount"},
+	EnablePaymentsToAccount: BoolField{"EnablePaymentsToAccount"},
+	UpdatedDateUTC:          DateField{"UpdatedDateUTC"},
+}
+
+// ItemFields are the filterable elements of an Item
+type ItemFields struct {
+	ItemID               GUIDField
+	Code                 StringField
+	Name                 StringField
+	Description          StringField
+	IsSold               BoolField
+	IsPurchased          BoolField
+	IsTrackedAsInventory BoolField
+	QuantityOnHand       DecimalField
+	UpdatedDateUTC       DateField
+}
+
+// Item references the elements of an Item
+var Item = ItemFields{
+	ItemID:               GUIDField{"ItemID"},
+	Code:                 StringField{"Code"},
+	Name:                 StringField{"Name"},
+	Description:          StringField{"Description"},
+	IsSold:               BoolField{"IsSold"},
+	IsPurchased:          BoolField{"IsPurchased"},
+	IsTrackedAsInventory: BoolField{"IsTrackedAsInventory"},
+	QuantityOnHand:       DecimalField{"QuantityOnHand"},
+	UpdatedDateUTC:       DateField{"UpdatedDateUTC"},
+}
+
+// ManualJournalFields are the filterable elements of a ManualJournal
+type ManualJournalFields struct {
+	ManualJournalID GUIDField
+	Narration       StringField
+	Status          StringField
+	Date            DateField
+	UpdatedDateUTC  DateField
+}
+
+// ManualJournal references the elements of a ManualJournal
+var ManualJournal = ManualJournalFields{
+	ManualJournalID: GUIDField{"ManualJournalID"},
+	Narration:       StringField{"Narration"},
+	Status:          StringField{"Status"},
+	Date:            DateField{"Date"},
+	UpdatedDateUTC:  DateField{"UpdatedDateUTC"},
+}
+
+// PurchaseOrderFields are the filterable elements of a PurchaseOrder
+type PurchaseOrderFields struct {
+	PurchaseOrderID     GUIDField
+	PurchaseOrderNumber StringField
+	Reference           StringField
+	Status              StringField
+	Contact             ContactFields
+	Date                DateField
+	DeliveryDate        DateField
+	CurrencyCode        StringField
+	Total               DecimalField
+	UpdatedDateUTC      DateField
+}
+
+// PurchaseOrder references the elements of a PurchaseOrder
+var PurchaseOrder = PurchaseOrderFields{
+	PurchaseOrderID:     GUIDField{"PurchaseOrderID"},
+	PurchaseOrderNumber: StringField{"PurchaseOrderNumber"},
+	Reference:           StringField{"Reference"},
+	Status:              StringField{"Status"},
+	Contact:             contactFields("Contact."),
+	Date:                DateField{"Date"},
+	DeliveryDate:        DateField{"DeliveryDate"},
+	CurrencyCode:        StringField{"CurrencyCode"},
+	Total:               DecimalField{"Total"},
+	UpdatedDateUTC:      DateField{"UpdatedDateUTC"},
+}
+
+// PrepaymentFields are the filterable elements of an Overpayment or a Prepayment
+type PrepaymentFields struct {
+	Type            StringField
+	Status          StringField
+	Contact         ContactFields
+	Date            DateField
+	CurrencyCode    StringField
+	Total           DecimalField
+	RemainingCredit DecimalField
+	UpdatedDateUTC  DateField
+}
+
+// Overpayment references the elements of an Overpayment
+var Overpayment = prepaymentFields()
+
+// Prepayment references the elements of a Prepayment
+var Prepayment = prepaymentFields()
+
+func prepaymentFields() PrepaymentFields {
+	return PrepaymentFields{
+		Type:            StringField{"Type"},
+		Status:          StringField{"Status"},
+		Contact:         contactFields("Contact."),
+		Date:            DateField{"Date"},
+		CurrencyCode:    StringField{"CurrencyCode"},
+		Total:           DecimalField{"Total"},
+		RemainingCredit: DecimalField{"RemainingCredit"},
+		UpdatedDateUTC:  DateField{"UpdatedDateUTC"},
+	}
+}
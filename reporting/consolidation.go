@@ -0,0 +1,330 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// accountLabel matches trial balance labels such as "Sales (200)"
+var accountLabel = regexp.MustCompile(`^(.*) \(([^()]+)\)$`)
+
+// Tenant is a single Xero organisation taking part in a consolidation
+type Tenant struct {
+	// Name identifies the tenant in the consolidated output
+	Name string
+
+	// Provider and Session used to run the tenant's trial balance - with OAuth2 set the TenantID on the provider
+	Provider xerogolang.IProvider
+	Session  goth.Session
+
+	// Base currency of the organisation
+	Currency string
+
+	// AccountMap maps the tenant's account codes onto the shared chart. Codes that are not in the map keep their own code
+	AccountMap map[string]string
+}
+
+// EliminationRule removes intercompany balances from the consolidation
+type EliminationRule struct {
+	Name string
+
+	// Shared chart account codes whose balances are eliminated e.g. intercompany receivable and payable
+	AccountCodes []string
+
+	// Names of the tenants the rule applies to - all tenants when empty
+	Tenants []string
+
+	// Shared chart account code that takes any difference left after elimination. When empty a difference is an error
+	DifferenceAccountCode string
+}
+
+// ConsolidationOptions control how trial balances are consolidated
+type ConsolidationOptions struct {
+	// Date of the trial balances
+	Date time.Time
+
+	// Only include cash transactions
+	PaymentsOnly bool
+
+	// Currency of the consolidated trial balance
+	ReportingCurrency string
+
+	// Rates converts the balances of tenants to the ReportingCurrency, keyed by tenant currency code. Like a
+	// CurrencyRate, a rate is the units of the tenant currency per unit of the reporting currency and balances
+	// are divided by it - see xerogolang.Money.Convert
+	Rates map[string]decimal.Decimal
+
+	// Eliminations are applied in order after the balances are converted
+	Eliminations []EliminationRule
+
+	// AccountNames names the accounts of the shared chart - the first tenant name found is used otherwise
+	AccountNames map[string]string
+
+	// StrictMapping makes accounts that are not in a tenant's AccountMap an error
+	StrictMapping bool
+}
+
+// TenantTrialBalance is the parsed trial balance of a single tenant
+type TenantTrialBalance struct {
+	Tenant *Tenant
+	Report *Report
+}
+
+// ConsolidatedLine is a single account of the consolidated trial balance. Balances are net debits - credits are negative
+type ConsolidatedLine struct {
+	AccountCode string
+	AccountName string
+
+	// ByTenant holds each tenant's balance in the reporting currency before elimination
+	ByTenant map[string]decimal.Decimal
+
+	// Eliminated is the total elimination posted to the account
+	Eliminated decimal.Decimal
+
+	// Balance is the consolidated balance after elimination
+	Balance decimal.Decimal
+}
+
+// Debit returns the balance when it is a debit
+func (l *ConsolidatedLine) Debit() decimal.Decimal {
+	if l.Balance.IsPositive() {
+		return l.Balance
+	}
+	return decimal.Zero
+}
+
+// Credit returns the balance as a positive amount when it is a credit
+func (l *ConsolidatedLine) Credit() decimal.Decimal {
+	if l.Balance.IsNegative() {
+		return l.Balance.Neg()
+	}
+	return decimal.Zero
+}
+
+// Elimination records the result of applying an EliminationRule
+type Elimination struct {
+	Rule EliminationRule
+
+	// Eliminated holds the amount removed from each account
+	Eliminated map[string]decimal.Decimal
+
+	// Difference is the amount posted to the DifferenceAccountCode
+	Difference decimal.Decimal
+}
+
+// ConsolidatedTrialBalance is the trial balance of a group of tenants
+type ConsolidatedTrialBalance struct {
+	Date     time.Time
+	Currency string
+
+	// Lines ordered by account code
+	Lines []*ConsolidatedLine
+
+	Eliminations []Elimination
+}
+
+// Line returns the line of an account code
+func (c *ConsolidatedTrialBalance) Line(accountCode string) *ConsolidatedLine {
+	for _, line := range c.Lines {
+		if line.AccountCode == accountCode {
+			return line
+		}
+	}
+	return nil
+}
+
+// Totals returns the total debits and credits of the consolidated trial balance
+func (c *ConsolidatedTrialBalance) Totals() (debit decimal.Decimal, credit decimal.Decimal) {
+	for _, line := range c.Lines {
+		debit = debit.Add(line.Debit())
+		credit = credit.Add(line.Credit())
+	}
+	return debit, credit
+}
+
+// RunConsolidatedTrialBalance runs the trial balance of every tenant at the date of options and consolidates them
+func RunConsolidatedTrialBalance(ctx context.Context, tenants []*Tenant, options ConsolidationOptions) (*ConsolidatedTrialBalance, error) {
+	balances := []TenantTrialBalance{}
+	for _, tenant := range tenants {
+		reports, err := accounting.RunTrialBalanceWithOptions(ctx, tenant.Provider, tenant.Session, &accounting.TrialBalanceOptions{
+			Date:         options.Date,
+			PaymentsOnly: options.PaymentsOnly,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %s", tenant.Name, err.Error())
+		}
+		if len(reports.Reports) == 0 {
+			return nil, fmt.Errorf("tenant %s: the trial balance returned no report", tenant.Name)
+		}
+		balances = append(balances, TenantTrialBalance{Tenant: tenant, Report: Parse(reports.Reports[0])})
+	}
+	return Consolidate(balances, options)
+}
+
+// Consolidate maps, converts and eliminates the trial balances of several tenants
+func Consolidate(balances []TenantTrialBalance, options ConsolidationOptions) (*ConsolidatedTrialBalance, error) {
+	consolidated := &ConsolidatedTrialBalance{
+		Date:         options.Date,
+		Currency:     options.ReportingCurrency,
+		Lines:        []*ConsolidatedLine{},
+		Eliminations: []Elimination{},
+	}
+	lines := map[string]*ConsolidatedLine{}
+	line := func(code string, name string) *ConsolidatedLine {
+		if l, ok := lines[code]; ok {
+			return l
+		}
+		if options.AccountNames[code] != "" {
+			name = options.AccountNames[code]
+		}
+		l := &ConsolidatedLine{AccountCode: code, AccountName: name, ByTenant: map[string]decimal.Decimal{}}
+		lines[code] = l
+		consolidated.Lines = append(consolidated.Lines, l)
+		return l
+	}
+
+	for _, balance := range balances {
+		tenant := balance.Tenant
+		rate := decimal.NewFromInt(1)
+		currency := options.ReportingCurrency
+		if currency == "" {
+			currency = tenant.Currency
+		}
+		if tenant.Currency != "" && options.ReportingCurrency != "" && tenant.Currency != options.ReportingCurrency {
+			r, ok := options.Rates[tenant.Currency]
+			if !ok {
+				return nil, fmt.Errorf("tenant %s: no rate to convert %s to %s", tenant.Name, tenant.Currency, options.ReportingCurrency)
+			}
+			rate = r
+		}
+
+		accounts, err := TrialBalanceAccounts(balance.Report)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %s", tenant.Name, err.Error())
+		}
+		for _, account := range accounts {
+			code, mapped := tenant.AccountMap[account.Code]
+			if !mapped {
+				if options.StrictMapping {
+					return nil, fmt.Errorf("tenant %s: account %s is not mapped to the shared chart", tenant.Name, account.Code)
+				}
+				code = account.Code
+			}
+			converted, err := xerogolang.NewMoney(account.Balance, tenant.Currency).Convert(currency, rate)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %s: %s", tenant.Name, err.Error())
+			}
+			l := line(code, account.Name)
+			l.ByTenant[tenant.Name] = l.ByTenant[tenant.Name].Add(converted.Round().Amount())
+		}
+	}
+
+	for _, l := range consolidated.Lines {
+		l.Balance = decimal.Zero
+		for _, amount := range l.ByTenant {
+			l.Balance = l.Balance.Add(amount)
+		}
+	}
+
+	for _, rule := range options.Eliminations {
+		elimination := Elimination{Rule: rule, Eliminated: map[string]decimal.Decimal{}}
+		for _, code := range rule.AccountCodes {
+			l, ok := lines[code]
+			if !ok {
+				continue
+			}
+			amount := decimal.Zero
+			for tenant, balance := range l.ByTenant {
+				if len(rule.Tenants) == 0 || containsString(rule.Tenants, tenant) {
+					amount = amount.Add(balance)
+				}
+			}
+			l.Eliminated = l.Eliminated.Sub(amount)
+			l.Balance = l.Balance.Sub(amount)
+			elimination.Eliminated[code] = amount
+			elimination.Difference = elimination.Difference.Add(amount)
+		}
+		if !elimination.Difference.IsZero() {
+			if rule.DifferenceAccountCode == "" {
+				return nil, fmt.Errorf("elimination %s leaves a difference of %s", rule.Name, elimination.Difference.String())
+			}
+			l := line(rule.DifferenceAccountCode, "")
+			l.Eliminated = l.Eliminated.Add(elimination.Difference)
+			l.Balance = l.Balance.Add(elimination.Difference)
+		}
+		consolidated.Eliminations = append(consolidated.Eliminations, elimination)
+	}
+
+	sort.SliceStable(consolidated.Lines, func(i, j int) bool {
+		return consolidated.Lines[i].AccountCode < consolidated.Lines[j].AccountCode
+	})
+	return consolidated, nil
+}
+
+// TrialBalanceAccount is the balance of a single account on a trial balance
+type TrialBalanceAccount struct {
+	AccountID string
+	Code      string
+	Name      string
+
+	// Balance is the net debit - credits are negative
+	Balance decimal.Decimal
+}
+
+// TrialBalanceAccounts reads the account balances of a parsed trial balance. The year to date columns are
+// used when the report has them, so balance sheet accounts carry their full balance
+func TrialBalanceAccounts(report *Report) ([]TrialBalanceAccount, error) {
+	debitColumn, creditColumn := "Debit", "Credit"
+	for _, column := range report.Columns {
+		if column == "YTD Debit" {
+			debitColumn, creditColumn = "YTD Debit", "YTD Credit"
+		}
+	}
+
+	accounts := []TrialBalanceAccount{}
+	for _, row := range report.AccountRows() {
+		account := TrialBalanceAccount{
+			AccountID: row.AccountID(),
+			Code:      row.Label(),
+			Name:      row.Label(),
+		}
+		if match := accountLabel.FindStringSubmatch(row.Label()); match != nil {
+			account.Name, account.Code = match[1], match[2]
+		}
+
+		for _, column := range []string{debitColumn, creditColumn} {
+			cell, ok := row.Cell(column)
+			if !ok {
+				return nil, errors.New("the trial balance has no " + column + " column")
+			}
+			if !cell.IsNumeric {
+				continue
+			}
+			if column == debitColumn {
+				account.Balance = account.Balance.Add(cell.Amount)
+			} else {
+				account.Balance = account.Balance.Sub(cell.Amount)
+			}
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
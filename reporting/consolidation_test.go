@@ -0,0 +1,102 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTrialBalance(t *testing.T, rows ...[3]string) *Report {
+	body := ""
+	for n, row := range rows {
+		if n > 0 {
+			body += ","
+		}
+		body += fmt.Sprintf(`{"RowType": "Row", "Cells": [
+			{"Value": %q, "Attributes": [{"Value": "id-%d", "Id": "account"}]},
+			{"Value": ""}, {"Value": ""}, {"Value": %q}, {"Value": %q}
+		]}`, row[0], n, row[1], row[2])
+	}
+	raw := `{"Reports": [{"ReportID": "TrialBalance", "ReportName": "Trial Balance", "Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Account"}, {"Value": "Debit"}, {"Value": "Credit"}, {"Value": "YTD Debit"}, {"Value": "YTD Credit"}]},
+		{"RowType": "Section", "Title": "Accounts", "Rows": [` + body + `]}
+	]}]}`
+
+	var reports accounting.Reports
+	if err := json.Unmarshal([]byte(raw), &reports); err != nil {
+		t.Fatal(err)
+	}
+	return ParseReports(&reports)[0]
+}
+
+func Test_Consolidate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	parent := &Tenant{Name: "Parent", Currency: "NZD"}
+	child := &Tenant{Name: "Child", Currency: "AUD", AccountMap: map[string]string{"610": "611", "800": "801"}}
+	balances := []TenantTrialBalance{
+		{Tenant: parent, Report: testTrialBalance(t,
+			[3]string{"Bank (090)", "1,000.00", ""},
+			[3]string{"Intercompany Receivable (611)", "250.00", ""},
+			[3]string{"Equity (300)", "", "1,250.00"},
+		)},
+		{Tenant: child, Report: testTrialBalance(t,
+			[3]string{"Bank (090)", "300.00", ""},
+			[3]string{"Intercompany Payable (800)", "", "200.00"},
+			[3]string{"Equity (300)", "", "100.00"},
+		)},
+	}
+	options := ConsolidationOptions{
+		ReportingCurrency: "NZD",
+		Rates:             map[string]decimal.Decimal{"AUD": decimal.RequireFromString("0.8")},
+		Eliminations: []EliminationRule{
+			{Name: "Intercompany", AccountCodes: []string{"611", "801"}, DifferenceAccountCode: "999"},
+		},
+		AccountNames: map[string]string{"999": "Elimination Difference"},
+	}
+
+	consolidated, err := Consolidate(balances, options)
+	a.NoError(err)
+
+	bank := consolidated.Line("090")
+	a.True(decimal.NewFromInt(1375).Equal(bank.Balance))
+	a.True(decimal.NewFromInt(375).Equal(bank.ByTenant["Child"]))
+	a.True(consolidated.Line("611").Balance.IsZero())
+	a.True(consolidated.Line("801").Balance.IsZero())
+	a.Nil(consolidated.Line("999"))
+
+	debit, credit := consolidated.Totals()
+	a.True(debit.Equal(credit))
+	a.Equal("090", consolidated.Lines[0].AccountCode)
+
+	balances[1].Report = testTrialBalance(t, [3]string{"Intercompany Payable (800)", "", "150.00"})
+	consolidated, err = Consolidate(balances, options)
+	a.NoError(err)
+	a.Equal("Elimination Difference", consolidated.Line("999").AccountName)
+	a.True(decimal.RequireFromString("62.5").Equal(consolidated.Line("999").Balance))
+
+	options.Eliminations[0].DifferenceAccountCode = ""
+	_, err = Consolidate(balances, options)
+	a.Error(err)
+
+	delete(options.Rates, "AUD")
+	_, err = Consolidate(balances, options)
+	a.Error(err)
+
+	options.StrictMapping = true
+	child.Currency = "NZD"
+	_, err = Consolidate(balances, options)
+	a.Error(err)
+
+	//balances are rounded to whole yen in a JPY consolidation
+	yen, err := Consolidate([]TenantTrialBalance{{Tenant: &Tenant{Name: "Child", Currency: "NZD"}, Report: testTrialBalance(t,
+		[3]string{"Bank (090)", "100.55", ""},
+	)}}, ConsolidationOptions{ReportingCurrency: "JPY", Rates: map[string]decimal.Decimal{"NZD": decimal.RequireFromString("0.0112")}})
+	a.NoError(err)
+	a.Equal("8978", yen.Line("090").Balance.String())
+}
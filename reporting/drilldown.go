@@ -0,0 +1,206 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// DrillDownOptions describe the period a report figure covers
+type DrillDownOptions struct {
+	// Start of the period - leave empty for balance sheet figures, which include every journal up to ToDate
+	FromDate time.Time
+
+	// End of the period - leave empty to include every journal from FromDate
+	ToDate time.Time
+
+	// Only include cash transactions, as with the paymentsOnly report option
+	PaymentsOnly bool
+
+	// Also fetch the document behind each journal
+	IncludeSources bool
+
+	// Journals to drill into, e.g. from FindDrillDownJournals. Fetch them once when drilling into several
+	// figures - when nil every journal of the organisation is fetched for each figure
+	Journals []accounting.Journal
+}
+
+// JournalEntry is a single journal line together with the journal it belongs to
+type JournalEntry struct {
+	Journal accounting.Journal
+	Line    accounting.JournalLine
+}
+
+// Source is the document that created one or more journals
+type Source struct {
+	SourceID   string
+	SourceType string
+
+	// Document is the collection returned by the matching Find function e.g. *accounting.Invoices
+	// for ACCREC and ACCPAY journals. It is nil for source types that cannot be fetched
+	Document interface{}
+}
+
+// DrillDown lists the journal lines behind a report figure
+type DrillDown struct {
+	AccountID string
+
+	// Entries in journal order
+	Entries []JournalEntry
+
+	// NetAmount is the sum of the NetAmount of every entry - positive for a net debit and negative for a net credit
+	NetAmount decimal.Decimal
+
+	// Sources of the entries - only set when IncludeSources was asked for
+	Sources []Source
+}
+
+// DrillDownCell returns the journal lines behind the account figure of a row. The AccountID is taken from
+// the attributes of the cell in column, falling back to the AccountID of the row
+func DrillDownCell(ctx context.Context, provider xerogolang.IProvider, session goth.Session, row *Row, column string, options DrillDownOptions) (*DrillDown, error) {
+	accountID := row.AccountID()
+	if cell, ok := row.Cell(column); ok && cell.Attributes[AttributeAccount] != "" {
+		accountID = cell.Attributes[AttributeAccount]
+	}
+	if accountID == "" {
+		return nil, fmt.Errorf("row %q is not an account row", row.Label())
+	}
+	return DrillDownAccount(ctx, provider, session, accountID, options)
+}
+
+// FindDrillDownJournals pages through the journals of the organisation, to be passed to several drill downs
+// in DrillDownOptions.Journals. Xero numbers journals in the order they were posted rather than by JournalDate,
+// so every journal is fetched
+func FindDrillDownJournals(ctx context.Context, provider xerogolang.IProvider, session goth.Session, paymentsOnly bool) ([]accounting.Journal, error) {
+	var querystringParameters map[string]string
+	if paymentsOnly {
+		querystringParameters = map[string]string{"paymentsOnly": "true"}
+	}
+
+	journals := []accounting.Journal{}
+	err := accounting.EachJournal(ctx, provider, session, 0, querystringParameters, func(journal accounting.Journal) error {
+		journals = append(journals, journal)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return journals, nil
+}
+
+// DrillDownAccount returns the lines posted to accountID within the period of options. The journals are
+// taken from options, or fetched with FindDrillDownJournals when options has none
+func DrillDownAccount(ctx context.Context, provider xerogolang.IProvider, session goth.Session, accountID string, options DrillDownOptions) (*DrillDown, error) {
+	journals := options.Journals
+	if journals == nil {
+		var err error
+		if journals, err = FindDrillDownJournals(ctx, provider, session, options.PaymentsOnly); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := MatchJournals(journals, accountID, options.FromDate, options.ToDate)
+	if err != nil {
+		return nil, err
+	}
+
+	drillDown := &DrillDown{
+		AccountID: accountID,
+		Entries:   entries,
+		NetAmount: decimal.Zero,
+		Sources:   []Source{},
+	}
+	for _, entry := range entries {
+		drillDown.NetAmount = drillDown.NetAmount.Add(entry.Line.NetAmount)
+	}
+
+	if options.IncludeSources {
+		seen := map[string]bool{}
+		for _, entry := range entries {
+			if entry.Journal.SourceID == "" || seen[entry.Journal.SourceID] {
+				continue
+			}
+			seen[entry.Journal.SourceID] = true
+
+			document, err := FindSource(ctx, provider, session, entry.Journal.SourceType, entry.Journal.SourceID)
+			if err != nil {
+				return nil, err
+			}
+			drillDown.Sources = append(drillDown.Sources, Source{
+				SourceID:   entry.Journal.SourceID,
+				SourceType: entry.Journal.SourceType,
+				Document:   document,
+			})
+		}
+	}
+
+	return drillDown, nil
+}
+
+// MatchJournals returns the lines of journals posted to accountID with a JournalDate between fromDate and toDate.
+// Either date can be left empty to leave that end of the period open
+func MatchJournals(journals []accounting.Journal, accountID string, fromDate time.Time, toDate time.Time) ([]JournalEntry, error) {
+	entries := []JournalEntry{}
+	for _, journal := range journals {
+		date, err := parseDate(journal.JournalDate)
+		if err != nil {
+			return nil, fmt.Errorf("journal %d: %s", journal.JournalNumber, err.Error())
+		}
+		if !fromDate.IsZero() && date.Before(dateOnly(fromDate)) {
+			continue
+		}
+		if !toDate.IsZero() && date.After(dateOnly(toDate)) {
+			continue
+		}
+		for _, line := range journal.JournalLines {
+			if line.AccountID == accountID {
+				entries = append(entries, JournalEntry{Journal: journal, Line: line})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// FindSource fetches the document behind a journal given its SourceType and SourceID. It returns nil
+// for source types without a matching endpoint e.g. payroll journals
+func FindSource(ctx context.Context, provider xerogolang.IProvider, session goth.Session, sourceType string, sourceID string) (interface{}, error) {
+	switch sourceType {
+	case "ACCREC", "ACCPAY":
+		return accounting.FindInvoice(ctx, provider, session, sourceID)
+	case "ACCRECCREDIT", "ACCPAYCREDIT":
+		return accounting.FindCreditNote(ctx, provider, session, sourceID)
+	case "ACCRECPAYMENT", "ACCPAYPAYMENT", "ARCREDITPAYMENT", "APCREDITPAYMENT":
+		return accounting.FindPayment(ctx, provider, session, sourceID)
+	case "CASHREC", "CASHPAID":
+		return accounting.FindBankTransaction(ctx, provider, session, sourceID)
+	case "TRANSFER":
+		return accounting.FindBankTransfer(ctx, provider, session, sourceID)
+	case "ARPREPAYMENT", "APPREPAYMENT":
+		return accounting.FindPrepayment(ctx, provider, session, sourceID)
+	case "AROVERPAYMENT", "APOVERPAYMENT":
+		return accounting.FindOverpayment(ctx, provider, session, sourceID)
+	case "MANJOURNAL":
+		return accounting.FindManualJournal(ctx, provider, session, sourceID)
+	case "EXPCLAIM":
+		return accounting.FindExpenseClaim(ctx, provider, session, sourceID)
+	}
+	return nil, nil
+}
+
+// parseDate returns the time of a date, failing when the date is not set
+func parseDate(date xerogolang.Date) (time.Time, error) {
+	if date.IsZero() {
+		return time.Time{}, fmt.Errorf("the date is not set")
+	}
+	return date.Time, nil
+}
+
+// dateOnly drops the time of day so dates compare by calendar day
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
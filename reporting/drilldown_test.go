@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProvider answers Find calls with canned responses keyed by endpoint and records the endpoints called
+type testProvider struct {
+	responses map[string]string
+	finds     []string
+}
+
+func (p *testProvider) Find(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, querystringParameters map[string]string) ([]byte, error) {
+	p.finds = append(p.finds, endpoint)
+	response, ok := p.responses[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("no response for GET %s", endpoint)
+	}
+	return []byte(response), nil
+}
+
+func (p *testProvider) Create(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected PUT %s", endpoint)
+}
+
+func (p *testProvider) Update(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string, body []byte) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected POST %s", endpoint)
+}
+
+func (p *testProvider) Remove(ctx context.Context, session goth.Session, endpoint string, additionalHeaders map[string]string) ([]byte, error) {
+	return nil, fmt.Errorf("unexpected DELETE %s", endpoint)
+}
+
+func Test_MatchJournals(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	journals := []accounting.Journal{
+		testJournal(1, "2024-03-10", 100, ""),
+		testJournal(2, "2023-12-01", 50, ""),
+		testJournal(3, "2024-04-05", 30, ""),
+	}
+	entries, err := MatchJournals(journals, "id-1", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 23, 0, 0, 0, time.UTC))
+	a.NoError(err)
+	a.Len(entries, 1)
+	a.Equal(1, entries[0].Journal.JournalNumber)
+	a.Equal("200", entries[0].Line.AccountCode)
+
+	//open ended periods
+	entries, err = MatchJournals(journals, "id-1", time.Time{}, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC))
+	a.NoError(err)
+	a.Len(entries, 2)
+	entries, err = MatchJournals(journals, "id-0", time.Time{}, time.Time{})
+	a.NoError(err)
+	a.Len(entries, 3)
+
+	_, err = MatchJournals([]accounting.Journal{{JournalNumber: 4}}, "id-1", time.Time{}, time.Time{})
+	a.EqualError(err, "journal 4: the date is not set")
+}
+
+func Test_DrillDownCell(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	options := DrillDownOptions{Journals: []accounting.Journal{
+		testJournal(1, "2024-03-10", 100, ""),
+		testJournal(2, "2024-03-12", 50, ""),
+	}}
+	provider := &testProvider{}
+	row := &Row{Type: RowTypeRow, Cells: []Cell{
+		{Value: "Sales", Attributes: map[string]string{AttributeAccount: "id-0"}},
+		{Column: "31 Mar 24", Value: "150.00", Attributes: map[string]string{AttributeAccount: "id-1"}},
+		{Column: "29 Feb 24", Value: "0.00", Attributes: map[string]string{}},
+	}}
+
+	//the account of the cell wins over the account of the row
+	drillDown, err := DrillDownCell(context.Background(), provider, nil, row, "31 Mar 24", options)
+	a.NoError(err)
+	a.Equal("id-1", drillDown.AccountID)
+	a.Len(drillDown.Entries, 2)
+	a.True(decimal.NewFromInt(-150).Equal(drillDown.NetAmount))
+
+	//a cell without an account falls back to the account of the row
+	drillDown, err = DrillDownCell(context.Background(), provider, nil, row, "29 Feb 24", options)
+	a.NoError(err)
+	a.Equal("id-0", drillDown.AccountID)
+	a.True(decimal.NewFromInt(150).Equal(drillDown.NetAmount))
+	a.Empty(provider.finds)
+
+	_, err = DrillDownCell(context.Background(), provider, nil, &Row{Cells: []Cell{{Value: "Net Profit"}}}, "31 Mar 24", options)
+	a.EqualError(err, `row "Net Profit" is not an account row`)
+
+	//without journals they are fetched from the organisation
+	provider.responses = map[string]string{"Journals": `{"Journals":[{"JournalID":"j1","JournalNumber":1,"JournalDate":"2024-03-10","SourceID":"inv-1","SourceType":"ACCREC",
+		"JournalLines":[{"AccountID":"id-1","NetAmount":-100}]}]}`, "Invoices/inv-1": `{"Invoices":[{"InvoiceID":"inv-1"}]}`}
+	drillDown, err = DrillDownCell(context.Background(), provider, nil, row, "31 Mar 24", DrillDownOptions{IncludeSources: true})
+	a.NoError(err)
+	a.Len(drillDown.Entries, 1)
+	a.Len(drillDown.Sources, 1)
+	a.Equal([]string{"Journals", "Invoices/inv-1"}, provider.finds)
+}
+
+func Test_FindSource(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	provider := &testProvider{responses: map[string]string{
+		"Invoices/s1":         `{"Invoices":[{"InvoiceID":"s1"}]}`,
+		"CreditNotes/s1":      `{"CreditNotes":[{"CreditNoteID":"s1"}]}`,
+		"Payments/s1":         `{"Payments":[{"PaymentID":"s1"}]}`,
+		"BankTransactions/s1": `{"BankTransactions":[{"BankTransactionID":"s1"}]}`,
+		"BankTransfers/s1":    `{"BankTransfers":[{"BankTransferID":"s1"}]}`,
+		"Prepayments/s1":      `{"Prepayments":[{"PrepaymentID":"s1"}]}`,
+		"Overpayments/s1":     `{"Overpayments":[{"OverpaymentID":"s1"}]}`,
+		"ManualJournals/s1":   `{"ManualJournals":[{"ManualJournalID":"s1"}]}`,
+		"ExpenseClaims/s1":    `{"ExpenseClaims":[{"ExpenseClaimID":"s1"}]}`,
+	}}
+
+	for sourceType, document := range map[string]interface{}{
+		"ACCREC":          &accounting.Invoices{},
+		"ACCPAYCREDIT":    &accounting.CreditNotes{},
+		"APCREDITPAYMENT": &accounting.Payments{},
+		"CASHPAID":        &accounting.BankTransactions{},
+		"TRANSFER":        &accounting.BankTransfers{},
+		"ARPREPAYMENT":    &accounting.Prepayments{},
+		"APOVERPAYMENT":   &accounting.Overpayments{},
+		"MANJOURNAL":      &accounting.ManualJournals{},
+		"EXPCLAIM":        &accounting.ExpenseClaims{},
+	} {
+		found, err := FindSource(context.Background(), provider, nil, sourceType, "s1")
+		a.NoError(err, sourceType)
+		a.IsType(document, found, sourceType)
+	}
+	a.Len(provider.finds, 9)
+
+	found, err := FindSource(context.Background(), provider, nil, "PAYSLIP", "s1")
+	a.NoError(err)
+	a.Nil(found)
+	a.Len(provider.finds, 9)
+}
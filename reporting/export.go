@@ -0,0 +1,275 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportLayout decides how the section hierarchy is kept when a report is flattened
+type ExportLayout int
+
+const (
+	// LayoutIndent writes section titles as rows of their own and indents the rows beneath them
+	LayoutIndent ExportLayout = iota
+	// LayoutPath writes the section titles of every row into leading path columns
+	LayoutPath
+)
+
+// ExportOptions control how a report is flattened by the exporters
+type ExportOptions struct {
+	Layout ExportLayout
+
+	// Indent is repeated once per level of nesting with LayoutIndent - defaults to two spaces
+	Indent string
+}
+
+// FlatRow is a single line of a flattened report
+type FlatRow struct {
+	// Section for section title lines, otherwise Row or SummaryRow
+	Type RowType
+
+	// Titles of the sections holding the row starting at the top level
+	Path []string
+
+	// Nesting level of the row - rows of a top level section have depth 1
+	Depth int
+
+	// Label is the section title or the value of the first cell
+	Label string
+
+	// AccountID of the row when it is an account
+	AccountID string
+
+	// Cells after the label cell
+	Cells []Cell
+}
+
+// Flatten returns the report as a list of lines in display order. Section title lines are only
+// included with LayoutIndent
+func Flatten(report *Report, options ExportOptions) []FlatRow {
+	rows := []FlatRow{}
+	var walk func(sections []*Section)
+	walk = func(sections []*Section) {
+		for _, section := range sections {
+			depth := section.Depth()
+			if options.Layout == LayoutIndent && section.Title != "" {
+				rows = append(rows, FlatRow{
+					Type:  RowTypeSection,
+					Path:  section.Parent.pathOrEmpty(),
+					Depth: depth,
+					Label: section.Title,
+					Cells: []Cell{},
+				})
+			}
+			for _, row := range section.Rows {
+				rows = append(rows, flatRow(row, section, depth+1))
+			}
+			walk(section.Sections)
+			if section.Summary != nil {
+				rows = append(rows, flatRow(section.Summary, section, depth+1))
+			}
+		}
+	}
+	walk(report.Sections)
+	return rows
+}
+
+func (s *Section) pathOrEmpty() []string {
+	if s == nil {
+		return []string{}
+	}
+	return s.Path()
+}
+
+func flatRow(row *Row, section *Section, depth int) FlatRow {
+	flat := FlatRow{
+		Type:      row.Type,
+		Path:      section.Path(),
+		Depth:     depth,
+		Label:     row.Label(),
+		AccountID: row.AccountID(),
+		Cells:     []Cell{},
+	}
+	if len(row.Cells) > 1 {
+		flat.Cells = row.Cells[1:]
+	}
+	return flat
+}
+
+// table returns the heading and lines shared by the CSV and XLSX exporters.
+// Numeric cells are returned as their decimal value so they stay numeric
+func table(report *Report, options ExportOptions) ([]string, [][]tableCell) {
+	indent := options.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	flat := Flatten(report, options)
+
+	pathColumns := 0
+	if options.Layout == LayoutPath {
+		for _, row := range flat {
+			if len(row.Path) > pathColumns {
+				pathColumns = len(row.Path)
+			}
+		}
+		if pathColumns == 0 {
+			pathColumns = 1
+		}
+	}
+
+	heading := []string{}
+	for n := 0; n < pathColumns; n++ {
+		heading = append(heading, fmt.Sprintf("Section %d", n+1))
+	}
+	columns := report.Columns
+	if len(columns) == 0 {
+		columns = []string{""}
+	}
+	label := columns[0]
+	if label == "" {
+		label = "Account"
+	}
+	heading = append(heading, label)
+	heading = append(heading, columns[1:]...)
+
+	lines := [][]tableCell{}
+	for _, row := range flat {
+		line := []tableCell{}
+		for n := 0; n < pathColumns; n++ {
+			value := ""
+			if n < len(row.Path) {
+				value = row.Path[n]
+			}
+			line = append(line, tableCell{text: value})
+		}
+		label := row.Label
+		if options.Layout == LayoutIndent {
+			label = strings.Repeat(indent, row.Depth) + label
+		}
+		line = append(line, tableCell{text: label})
+		for _, cell := range row.Cells {
+			if cell.IsNumeric {
+				line = append(line, tableCell{text: cell.Amount.String(), numeric: true})
+			} else {
+				line = append(line, tableCell{text: cell.Value})
+			}
+		}
+		for len(line) < len(heading) {
+			line = append(line, tableCell{})
+		}
+		lines = append(lines, line)
+	}
+	return heading, lines
+}
+
+type tableCell struct {
+	text    string
+	numeric bool
+}
+
+// WriteCSV writes the report as CSV with a heading line. Numbers are written without thousands separators
+func WriteCSV(w io.Writer, report *Report, options ExportOptions) error {
+	heading, lines := table(report, options)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(heading); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		record := make([]string, len(line))
+		for n, cell := range line {
+			record[n] = cell.text
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonLine is a single line written by WriteJSONLines
+type jsonLine struct {
+	Report    string                     `json:"report"`
+	Type      RowType                    `json:"type"`
+	Path      []string                   `json:"path"`
+	Depth     int                        `json:"depth"`
+	Label     string                     `json:"label"`
+	AccountID string                     `json:"accountID,omitempty"`
+	Values    map[string]json.RawMessage `json:"values"`
+}
+
+// jsonValueKeys returns the key of each column in the values of a JSON line. Columns without a heading are
+// keyed by their position, e.g. "2", and repeated headings get their position added, e.g. "Total (3)". Real
+// headings are reserved first and a generated key that is taken gets the position added again, so the keys
+// are always unique
+func jsonValueKeys(columns []string) []string {
+	keys := make([]string, len(columns))
+	used := map[string]bool{}
+	for n, column := range columns {
+		if column != "" && !used[column] {
+			used[column] = true
+			keys[n] = column
+		}
+	}
+	for n, column := range columns {
+		if keys[n] != "" {
+			continue
+		}
+		key := fmt.Sprint(n)
+		if column != "" {
+			key = fmt.Sprintf("%s (%d)", column, n)
+		}
+		for used[key] {
+			key = fmt.Sprintf("%s (%d)", key, n)
+		}
+		used[key] = true
+		keys[n] = key
+	}
+	return keys
+}
+
+// WriteJSONLines writes one JSON object per row of the report. The section hierarchy is always
+// written as a path and numeric cells are written as JSON numbers. Values are keyed by the column
+// heading, or by the position of the column when the heading is empty or repeated
+func WriteJSONLines(w io.Writer, report *Report, options ExportOptions) error {
+	options.Layout = LayoutPath
+	keys := jsonValueKeys(report.Columns)
+	encoder := json.NewEncoder(w)
+	for _, row := range Flatten(report, options) {
+		line := jsonLine{
+			Report:    report.Name,
+			Type:      row.Type,
+			Path:      row.Path,
+			Depth:     row.Depth,
+			Label:     row.Label,
+			AccountID: row.AccountID,
+			Values:    map[string]json.RawMessage{},
+		}
+		for n, cell := range row.Cells {
+			var value []byte
+			var err error
+			if cell.IsNumeric {
+				value = []byte(cell.Amount.String())
+			} else {
+				value, err = json.Marshal(cell.Value)
+				if err != nil {
+					return err
+				}
+			}
+			//the label is the first cell of the row
+			key := fmt.Sprint(n + 1)
+			if n+1 < len(keys) {
+				key = keys[n+1]
+			}
+			line.Values[key] = value
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
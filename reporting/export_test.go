@@ -0,0 +1,89 @@
+package reporting
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_WriteCSV(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	report := testProfitAndLoss(t)
+
+	out := &bytes.Buffer{}
+	a.NoError(WriteCSV(out, report, ExportOptions{}))
+	a.Equal(`Account,31 Mar 24
+Income,
+"  Sales",1250
+"  Interest Income",-10.5
+"  Total Income",1239.5
+"  Net Profit",1239.5
+`, out.String())
+
+	out.Reset()
+	a.NoError(WriteCSV(out, report, ExportOptions{Layout: LayoutPath}))
+	a.Equal(`Section 1,Account,31 Mar 24
+Income,Sales,1250
+Income,Interest Income,-10.5
+Income,Total Income,1239.5
+,Net Profit,1239.5
+`, out.String())
+}
+
+func Test_WriteJSONLines(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	out := &bytes.Buffer{}
+	a.NoError(WriteJSONLines(out, testProfitAndLoss(t), ExportOptions{}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	a.Len(lines, 4)
+
+	var first map[string]interface{}
+	a.NoError(json.Unmarshal([]byte(lines[0]), &first))
+	a.Equal("Sales", first["label"])
+	a.Equal([]interface{}{"Income"}, first["path"])
+	a.Equal(1250.0, first["values"].(map[string]interface{})["31 Mar 24"])
+
+	a.Equal([]string{"0", "Total", "2", "Total (3)"}, jsonValueKeys([]string{"", "Total", "", "Total"}))
+	//generated keys never collide with real headings
+	a.Equal([]string{"X", "2", "2 (2)"}, jsonValueKeys([]string{"X", "2", ""}))
+	a.Equal([]string{"Total (3)", "Total", "2", "Total (3) (3)"}, jsonValueKeys([]string{"Total (3)", "Total", "", "Total"}))
+	a.Equal([]string{"1 (0)", "1", "1 (2)", "1 (0) (3)"}, jsonValueKeys([]string{"1 (0)", "1", "1", "1 (0)"}))
+}
+
+func Test_WriteXLSX(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	out := &bytes.Buffer{}
+	a.NoError(WriteXLSX(out, testProfitAndLoss(t), ExportOptions{}))
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	a.NoError(err)
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			f, err := file.Open()
+			a.NoError(err)
+			b, err := io.ReadAll(f)
+			a.NoError(err)
+			sheet = string(b)
+		}
+	}
+	a.Contains(sheet, `<c r="B3"><v>1250</v></c>`)
+	a.Contains(sheet, `<t xml:space="preserve">  Sales</t>`)
+
+	a.Equal("A", columnName(0))
+	a.Equal("AA", columnName(26))
+	a.Equal("Profit and Loss", xlsxSheetName("Profit and Loss"))
+}
@@ -0,0 +1,478 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/omniboost/xerogolang/query"
+	"github.com/shopspring/decimal"
+)
+
+// ForecastInterval is the length of each period of a cash flow forecast
+type ForecastInterval string
+
+// Forecast intervals
+const (
+	IntervalDay  ForecastInterval = "DAY"
+	IntervalWeek ForecastInterval = "WEEK"
+)
+
+// ForecastOverride changes when, or whether, matching invoices and bills are expected to be settled
+type ForecastOverride struct {
+	// Only apply to this contact - all contacts when empty
+	ContactID string
+
+	// Only apply to ACCREC or ACCPAY documents - both when empty
+	Type accounting.InvoiceType
+
+	// Move the expected date by this many days - negative days bring it forward
+	DelayDays int
+
+	// Leave matching documents out of the forecast
+	Exclude bool
+}
+
+// Scenario is a named set of overrides e.g. delay a slow paying customer by 14 days
+type Scenario struct {
+	Name      string
+	Overrides []ForecastOverride
+}
+
+// ForecastOptions control the periods and scenario of a cash flow forecast
+type ForecastOptions struct {
+	// First day of the forecast - defaults to today. Overdue documents are expected on this day
+	FromDate time.Time
+
+	// Last day of the forecast
+	ToDate time.Time
+
+	// Length of each period - defaults to a day
+	Interval ForecastInterval
+
+	// Leave repeating invoice templates out of the forecast
+	ExcludeRepeating bool
+
+	// Base currency of the organisation e.g. NZD. Converted amounts are rounded to its minor units. Without it
+	// every invoice and template has to be in one currency, and no invoice can have a CurrencyRate other than 1
+	BaseCurrency string
+
+	// Rates converts repeating invoice templates, which have no CurrencyRate, to the BaseCurrency, keyed by
+	// template currency code. Like a CurrencyRate, a rate is the units of the template currency per unit of the
+	// base currency and amounts are divided by it - see xerogolang.Money.Convert
+	Rates map[string]decimal.Decimal
+
+	Scenario Scenario
+}
+
+// BankBalance is the closing balance of a bank account on the Bank Summary report
+type BankBalance struct {
+	AccountID string
+	Name      string
+	Balance   decimal.Decimal
+}
+
+// ForecastData holds the balances and documents a cash flow forecast is built from
+type ForecastData struct {
+	BankAccounts      []BankBalance
+	Invoices          []accounting.Invoice
+	RepeatingInvoices []accounting.RepeatingInvoice
+}
+
+// CashFlowItem is a single expected receipt or payment
+type CashFlowItem struct {
+	Date time.Time
+
+	ContactID   string
+	ContactName string
+
+	// ACCREC or ACCPAY
+	Type       accounting.InvoiceType
+	DocumentID string
+	Number     string
+
+	// Set for items projected from a repeating invoice template
+	Repeating bool
+
+	// Amount in the base currency - positive for cash in and negative for cash out
+	Amount decimal.Decimal
+}
+
+// ForecastPeriod is a single period of a cash flow forecast
+type ForecastPeriod struct {
+	FromDate time.Time
+	ToDate   time.Time
+
+	Opening decimal.Decimal
+	CashIn  decimal.Decimal
+	CashOut decimal.Decimal
+	Closing decimal.Decimal
+
+	Items []CashFlowItem
+}
+
+// CashFlowForecast projects the bank balance over a number of periods
+type CashFlowForecast struct {
+	Scenario string
+
+	// BankAccounts and their total Opening balance at the start of the forecast
+	BankAccounts []BankBalance
+	Opening      decimal.Decimal
+
+	Periods []ForecastPeriod
+}
+
+// Closing returns the projected balance at the end of the forecast
+func (f *CashFlowForecast) Closing() decimal.Decimal {
+	if len(f.Periods) == 0 {
+		return f.Opening
+	}
+	return f.Periods[len(f.Periods)-1].Closing
+}
+
+// Lowest returns the period with the lowest closing balance
+func (f *CashFlowForecast) Lowest() *ForecastPeriod {
+	var lowest *ForecastPeriod
+	for n := range f.Periods {
+		if lowest == nil || f.Periods[n].Closing.LessThan(lowest.Closing) {
+			lowest = &f.Periods[n]
+		}
+	}
+	return lowest
+}
+
+// RunCashFlowForecast fetches bank balances, open invoices and bills and repeating invoice templates and builds a forecast
+func RunCashFlowForecast(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options ForecastOptions) (*CashFlowForecast, error) {
+	fromDate := options.FromDate
+	if fromDate.IsZero() {
+		fromDate = time.Now()
+	}
+	data, err := FindForecastData(ctx, provider, session, fromDate)
+	if err != nil {
+		return nil, err
+	}
+	return BuildCashFlowForecast(*data, options)
+}
+
+// FindForecastData fetches the bank balances at date from the Bank Summary report together with the authorised
+// invoices and bills and the repeating invoice templates of the organisation
+func FindForecastData(ctx context.Context, provider xerogolang.IProvider, session goth.Session, date time.Time) (*ForecastData, error) {
+	reports, err := accounting.RunBankSummaryWithOptions(ctx, provider, session, &accounting.BankReportOptions{
+		FromDate: date,
+		ToDate:   date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reports.Reports) == 0 {
+		return nil, errors.New("the bank summary returned no report")
+	}
+	data := &ForecastData{BankAccounts: BankBalances(Parse(reports.Reports[0]))}
+
+	for page := 1; ; page++ {
+		invoices, err := accounting.FindInvoicesWithOptions(ctx, provider, session, &accounting.InvoicesOptions{
+			ListOptions: accounting.ListOptions{Where: query.Invoice.AmountDue.Gt(decimal.Zero), Page: page},
+			Statuses:    []string{"AUTHORISED"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.Invoices = append(data.Invoices, invoices.Invoices...)
+		if len(invoices.Invoices) < 100 {
+			break
+		}
+	}
+
+	repeatingInvoices, err := accounting.FindRepeatingInvoices(ctx, provider, session, nil)
+	if err != nil {
+		return nil, err
+	}
+	data.RepeatingInvoices = repeatingInvoices.RepeatingInvoices
+	return data, nil
+}
+
+// BankBalances reads the closing balance of every bank account on a parsed Bank Summary report
+func BankBalances(report *Report) []BankBalance {
+	column := "Closing Balance"
+	if !containsString(report.Columns, column) && len(report.Columns) > 0 {
+		column = report.Columns[len(report.Columns)-1]
+	}
+
+	balances := []BankBalance{}
+	for _, row := range report.Rows() {
+		if row.Type != RowTypeRow {
+			continue
+		}
+		if value, ok := row.Cell(column); ok && value.IsNumeric {
+			balances = append(balances, BankBalance{AccountID: row.AccountID(), Name: row.Label(), Balance: value.Amount})
+		}
+	}
+	return balances
+}
+
+// BuildCashFlowForecast projects cash in and out of open invoices, bills and repeating invoice templates
+// from the bank balances of data
+func BuildCashFlowForecast(data ForecastData, options ForecastOptions) (*CashFlowForecast, error) {
+	fromDate := dateOnly(options.FromDate)
+	if options.FromDate.IsZero() {
+		fromDate = dateOnly(time.Now())
+	}
+	toDate := dateOnly(options.ToDate)
+	if options.ToDate.IsZero() || toDate.Before(fromDate) {
+		return nil, errors.New("the forecast needs a ToDate on or after its FromDate")
+	}
+	step := 1
+	switch options.Interval {
+	case IntervalDay, "":
+	case IntervalWeek:
+		step = 7
+	default:
+		return nil, fmt.Errorf("interval must be DAY or WEEK, not %q", string(options.Interval))
+	}
+
+	items := []CashFlowItem{}
+	add := func(item CashFlowItem) {
+		//anything overdue is expected on the first day, before any delay is applied
+		if item.Date.Before(fromDate) {
+			item.Date = fromDate
+		}
+		for _, override := range options.Scenario.Overrides {
+			if override.ContactID != "" && override.ContactID != item.ContactID {
+				continue
+			}
+			if override.Type != "" && override.Type != item.Type {
+				continue
+			}
+			if override.Exclude {
+				return
+			}
+			item.Date = item.Date.AddDate(0, 0, override.DelayDays)
+		}
+		if item.Date.Before(fromDate) {
+			item.Date = fromDate
+		}
+		if item.Date.After(toDate) {
+			return
+		}
+		items = append(items, item)
+	}
+
+	//without a BaseCurrency nothing can be converted, so every amount has to be in one currency already
+	currencies := []string{}
+	sameCurrency := func(currencyCode string) error {
+		if options.BaseCurrency != "" || currencyCode == "" || containsString(currencies, currencyCode) {
+			return nil
+		}
+		currencies = append(currencies, currencyCode)
+		if len(currencies) > 1 {
+			sort.Strings(currencies)
+			return fmt.Errorf("the forecast has amounts in %s - set a BaseCurrency to convert them", strings.Join(currencies, ", "))
+		}
+		return nil
+	}
+
+	for _, invoice := range data.Invoices {
+		if invoice.Status != "AUTHORISED" || !invoice.AmountDue.IsPositive() {
+			continue
+		}
+		expected := invoice.DueDate
+		if invoice.Type == accounting.InvoiceTypeAccRec && !invoice.ExpectedPaymentDate.IsZero() {
+			expected = invoice.ExpectedPaymentDate
+		} else if invoice.Type == accounting.InvoiceTypeAccPay && !invoice.PlannedPaymentDate.IsZero() {
+			expected = invoice.PlannedPaymentDate
+		}
+		if expected.IsZero() {
+			expected = invoice.Date
+		}
+		date, err := parseDate(expected)
+		if err != nil {
+			return nil, fmt.Errorf("invoice %s: %s", invoice.InvoiceNumber, err.Error())
+		}
+
+		if err := sameCurrency(invoice.CurrencyCode); err != nil {
+			return nil, err
+		}
+		if options.BaseCurrency == "" && !invoice.CurrencyRate.IsZero() && !invoice.CurrencyRate.Equal(decimal.NewFromInt(1)) {
+			return nil, fmt.Errorf("invoice %s: %s is not the base currency - set a BaseCurrency to convert it", invoice.InvoiceNumber, invoice.CurrencyCode)
+		}
+		amount := invoice.AmountDue
+		if !invoice.CurrencyRate.IsZero() && options.BaseCurrency != "" {
+			base, err := invoice.BaseMoney(amount, options.BaseCurrency)
+			if err != nil {
+				return nil, fmt.Errorf("invoice %s: %s", invoice.InvoiceNumber, err.Error())
+			}
+			amount = base.Amount()
+		}
+		if invoice.Type == accounting.InvoiceTypeAccPay {
+			amount = amount.Neg()
+		}
+		add(CashFlowItem{
+			Date:        date,
+			ContactID:   invoice.Contact.ContactID,
+			ContactName: invoice.Contact.Name,
+			Type:        invoice.Type,
+			DocumentID:  invoice.InvoiceID,
+			Number:      invoice.InvoiceNumber,
+			Amount:      amount,
+		})
+	}
+
+	if !options.ExcludeRepeating {
+		for _, template := range data.RepeatingInvoices {
+			//draft templates do not raise invoices
+			if template.Status != "AUTHORISED" {
+				continue
+			}
+			dates, err := scheduledDates(template.Schedule, fromDate, toDate)
+			if err != nil {
+				return nil, fmt.Errorf("repeating invoice %s: %s", template.RepeatingInvoiceID, err.Error())
+			}
+			if err := sameCurrency(template.CurrencyCode); err != nil {
+				return nil, err
+			}
+			amount := template.Total
+			if template.CurrencyCode != "" && options.BaseCurrency != "" && template.CurrencyCode != options.BaseCurrency {
+				rate, ok := options.Rates[template.CurrencyCode]
+				if !ok {
+					return nil, fmt.Errorf("repeating invoice %s: no rate to convert %s to %s", template.RepeatingInvoiceID, template.CurrencyCode, options.BaseCurrency)
+				}
+				base, err := xerogolang.NewMoney(amount, template.CurrencyCode).Convert(options.BaseCurrency, rate)
+				if err != nil {
+					return nil, fmt.Errorf("repeating invoice %s: %s", template.RepeatingInvoiceID, err.Error())
+				}
+				amount = base.Amount()
+			}
+			if template.Type == accounting.InvoiceTypeAccPay {
+				amount = amount.Neg()
+			}
+			for _, date := range dates {
+				add(CashFlowItem{
+					Date:        scheduleDueDate(template.Schedule, date),
+					ContactID:   template.Contact.ContactID,
+					ContactName: template.Contact.Name,
+					Type:        template.Type,
+					DocumentID:  template.RepeatingInvoiceID,
+					Repeating:   true,
+					Amount:      amount,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.Before(items[j].Date)
+	})
+
+	forecast := &CashFlowForecast{
+		Scenario:     options.Scenario.Name,
+		BankAccounts: data.BankAccounts,
+		Periods:      []ForecastPeriod{},
+	}
+	for _, account := range data.BankAccounts {
+		forecast.Opening = forecast.Opening.Add(account.Balance)
+	}
+
+	balance := forecast.Opening
+	next := 0
+	for start := fromDate; !start.After(toDate); start = start.AddDate(0, 0, step) {
+		period := ForecastPeriod{
+			FromDate: start,
+			ToDate:   start.AddDate(0, 0, step-1),
+			Opening:  balance,
+			Items:    []CashFlowItem{},
+		}
+		if period.ToDate.After(toDate) {
+			period.ToDate = toDate
+		}
+		for ; next < len(items) && !items[next].Date.After(period.ToDate); next++ {
+			item := items[next]
+			if item.Amount.IsPositive() {
+				period.CashIn = period.CashIn.Add(item.Amount)
+			} else {
+				period.CashOut = period.CashOut.Sub(item.Amount)
+			}
+			period.Items = append(period.Items, item)
+		}
+		balance = balance.Add(period.CashIn).Sub(period.CashOut)
+		period.Closing = balance
+		forecast.Periods = append(forecast.Periods, period)
+	}
+	return forecast, nil
+}
+
+// scheduledDates returns the dates a repeating invoice schedule will raise invoices between fromDate and toDate
+func scheduledDates(schedule accounting.Schedule, fromDate time.Time, toDate time.Time) ([]time.Time, error) {
+	dates := []time.Time{}
+	if schedule.NextScheduledDate.IsZero() {
+		return dates, nil
+	}
+	next, err := parseDate(schedule.NextScheduledDate)
+	if err != nil {
+		return nil, err
+	}
+	var end time.Time
+	if !schedule.EndDate.IsZero() {
+		if end, err = parseDate(schedule.EndDate); err != nil {
+			return nil, err
+		}
+	}
+
+	period := int(schedule.Period)
+	if period < 1 {
+		period = 1
+	}
+	for n := 0; ; n++ {
+		var date time.Time
+		switch schedule.Unit {
+		case "WEEKLY":
+			date = next.AddDate(0, 0, 7*period*n)
+		case "MONTHLY":
+			date = addMonths(next, period*n)
+		default:
+			return nil, fmt.Errorf("unknown schedule unit %q", schedule.Unit)
+		}
+		if date.After(toDate) || (!end.IsZero() && date.After(end)) {
+			return dates, nil
+		}
+		if !date.Before(fromDate) {
+			dates = append(dates, date)
+		}
+	}
+}
+
+// addMonths adds months to a date, keeping to the last day of shorter months
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	return dayOfMonth(first, date.Day())
+}
+
+// dayOfMonth returns the given day of the month holding date, or the last day when the month is shorter
+func dayOfMonth(date time.Time, day int) time.Time {
+	last := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+	if day > last.Day() {
+		day = last.Day()
+	}
+	return time.Date(date.Year(), date.Month(), day, 0, 0, 0, 0, time.UTC)
+}
+
+// scheduleDueDate returns the due date of an invoice raised on date by a repeating invoice schedule
+func scheduleDueDate(schedule accounting.Schedule, date time.Time) time.Time {
+	days := int(schedule.DueDate)
+	switch schedule.DueDateType {
+	case "DAYSAFTERBILLDATE":
+		return date.AddDate(0, 0, days)
+	case "DAYSAFTERBILLMONTH":
+		return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+	case "OFCURRENTMONTH":
+		return dayOfMonth(date, days)
+	case "OFFOLLOWINGMONTH":
+		return dayOfMonth(time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC), days)
+	}
+	return date
+}
@@ -0,0 +1,96 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildCashFlowForecast(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	customer := accounting.Contact{ContactID: "c1", Name: "Ridgeway University"}
+	supplier := accounting.Contact{ContactID: "s1", Name: "PowerDirect"}
+	data := ForecastData{
+		BankAccounts: []BankBalance{{Name: "Business Bank Account", Balance: decimal.NewFromInt(1000)}},
+		Invoices: []accounting.Invoice{
+			{Type: "ACCREC", InvoiceID: "i1", Contact: customer, Status: "AUTHORISED", DueDate: testDate("2024-03-20T00:00:00"), AmountDue: decimal.NewFromInt(500)},
+			{Type: "ACCREC", InvoiceID: "i2", Contact: customer, Status: "AUTHORISED", DueDate: testDate("2024-04-01T00:00:00"), ExpectedPaymentDate: testDate("2024-04-10T00:00:00"), AmountDue: decimal.NewFromInt(300)},
+			{Type: "ACCPAY", InvoiceID: "b1", Contact: supplier, Status: "AUTHORISED", DueDate: testDate("2024-04-15T00:00:00"), PlannedPaymentDate: testDate("2024-04-03T00:00:00"), AmountDue: decimal.NewFromInt(1200)},
+			{Type: "ACCPAY", InvoiceID: "b2", Contact: supplier, Status: "PAID", DueDate: testDate("2024-04-03T00:00:00")},
+		},
+		RepeatingInvoices: []accounting.RepeatingInvoice{
+			{Type: "ACCREC", RepeatingInvoiceID: "r1", Contact: customer, Status: "AUTHORISED", Total: decimal.NewFromInt(100),
+				Schedule: accounting.Schedule{Period: 1, Unit: "WEEKLY", NextScheduledDate: testDate("2024-04-05T00:00:00"), DueDate: 7, DueDateType: "DAYSAFTERBILLDATE"}},
+			{Type: "ACCPAY", RepeatingInvoiceID: "r2", Contact: supplier, Status: "DRAFT", Total: decimal.NewFromInt(1000),
+				Schedule: accounting.Schedule{Period: 1, Unit: "WEEKLY", NextScheduledDate: testDate("2024-04-05T00:00:00"), DueDate: 7, DueDateType: "DAYSAFTERBILLDATE"}},
+		},
+	}
+	options := ForecastOptions{
+		FromDate: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:   time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC),
+		Interval: IntervalWeek,
+	}
+
+	forecast, err := BuildCashFlowForecast(data, options)
+	a.NoError(err)
+	a.Len(forecast.Periods, 4)
+	//the overdue invoice is expected on the first day
+	a.True(decimal.NewFromInt(500).Equal(forecast.Periods[0].CashIn))
+	a.True(decimal.NewFromInt(1200).Equal(forecast.Periods[0].CashOut))
+	a.True(decimal.NewFromInt(300).Equal(forecast.Periods[0].Closing))
+	//the expected payment date and the first repeating invoice due on 12 April
+	a.True(decimal.NewFromInt(400).Equal(forecast.Periods[1].CashIn))
+	a.True(decimal.NewFromInt(900).Equal(forecast.Closing()))
+	a.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), forecast.Lowest().FromDate)
+
+	options.Scenario = Scenario{Name: "Late customer", Overrides: []ForecastOverride{{ContactID: "c1", Type: "ACCREC", DelayDays: 14}}}
+	forecast, err = BuildCashFlowForecast(data, options)
+	a.NoError(err)
+	a.True(forecast.Periods[0].CashIn.IsZero())
+	a.True(decimal.NewFromInt(500).Equal(forecast.Periods[2].CashIn))
+	a.True(decimal.NewFromInt(-200).Equal(forecast.Lowest().Closing))
+
+	//a USD invoice in a JPY organisation is converted to whole yen
+	forecast, err = BuildCashFlowForecast(ForecastData{Invoices: []accounting.Invoice{
+		{Type: "ACCREC", InvoiceID: "i3", Contact: customer, Status: "AUTHORISED", DueDate: testDate("2024-04-02T00:00:00"), AmountDue: decimal.NewFromInt(100),
+			CurrencyCode: "USD", CurrencyRate: decimal.RequireFromString("0.0067")},
+	}}, ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate, Interval: IntervalWeek, BaseCurrency: "JPY"})
+	a.NoError(err)
+	a.Equal("14925", forecast.Periods[0].CashIn.String())
+
+	//repeating invoices are converted with the rate of their currency
+	template := data.RepeatingInvoices[0]
+	template.CurrencyCode = "USD"
+	forecast, err = BuildCashFlowForecast(ForecastData{RepeatingInvoices: []accounting.RepeatingInvoice{template}},
+		ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate, Interval: IntervalWeek, BaseCurrency: "NZD", Rates: map[string]decimal.Decimal{"USD": decimal.RequireFromString("0.6")}})
+	a.NoError(err)
+	a.Equal("166.67", forecast.Periods[1].CashIn.String())
+	_, err = BuildCashFlowForecast(ForecastData{RepeatingInvoices: []accounting.RepeatingInvoice{template}},
+		ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate, BaseCurrency: "NZD"})
+	a.EqualError(err, "repeating invoice r1: no rate to convert USD to NZD")
+
+	//without a BaseCurrency foreign amounts are refused rather than added up unconverted
+	usd := accounting.Invoice{Type: "ACCREC", InvoiceID: "i4", InvoiceNumber: "INV-004", Contact: customer, Status: "AUTHORISED", DueDate: testDate("2024-04-02T00:00:00"),
+		AmountDue: decimal.NewFromInt(100), CurrencyCode: "USD", CurrencyRate: decimal.RequireFromString("0.6")}
+	_, err = BuildCashFlowForecast(ForecastData{Invoices: []accounting.Invoice{usd}}, ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate})
+	a.EqualError(err, "invoice INV-004: USD is not the base currency - set a BaseCurrency to convert it")
+	usd.CurrencyRate = decimal.NewFromInt(1)
+	_, err = BuildCashFlowForecast(ForecastData{Invoices: []accounting.Invoice{usd}, RepeatingInvoices: []accounting.RepeatingInvoice{data.RepeatingInvoices[0]}},
+		ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate})
+	a.NoError(err)
+	_, err = BuildCashFlowForecast(ForecastData{Invoices: []accounting.Invoice{usd}, RepeatingInvoices: []accounting.RepeatingInvoice{template}},
+		ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate})
+	a.NoError(err)
+	template.CurrencyCode = "EUR"
+	_, err = BuildCashFlowForecast(ForecastData{Invoices: []accounting.Invoice{usd}, RepeatingInvoices: []accounting.RepeatingInvoice{template}},
+		ForecastOptions{FromDate: options.FromDate, ToDate: options.ToDate})
+	a.EqualError(err, "the forecast has amounts in EUR, USD - set a BaseCurrency to convert them")
+
+	a.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), addMonths(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 1))
+	a.Equal(time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC), scheduleDueDate(accounting.Schedule{DueDate: 20, DueDateType: "OFFOLLOWINGMONTH"}, time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,322 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// TrackingOption identifies an option of a tracking category on a journal line
+type TrackingOption struct {
+	Category string
+	Option   string
+}
+
+// Posting is a single journal line posted to a ledger account
+type Posting struct {
+	Date          time.Time
+	JournalID     string
+	JournalNumber int
+	SourceID      string
+	SourceType    string
+	Description   string
+
+	// Amount is positive for a debit and negative for a credit
+	Amount decimal.Decimal
+
+	// Balance is the running balance of the account after this posting
+	Balance decimal.Decimal
+
+	Tracking []TrackingOption
+}
+
+// LedgerAccount holds the postings of a single account
+type LedgerAccount struct {
+	AccountID string
+	Code      string
+	Name      string
+	Type      accounting.AccountType
+
+	postings []Posting
+	sorted   bool
+}
+
+// Postings returns the postings of the account ordered by date and journal number, with running balances
+func (a *LedgerAccount) Postings() []Posting {
+	if !a.sorted {
+		sort.SliceStable(a.postings, func(i, j int) bool {
+			if !a.postings[i].Date.Equal(a.postings[j].Date) {
+				return a.postings[i].Date.Before(a.postings[j].Date)
+			}
+			return a.postings[i].JournalNumber < a.postings[j].JournalNumber
+		})
+		balance := decimal.Zero
+		for n := range a.postings {
+			balance = balance.Add(a.postings[n].Amount)
+			a.postings[n].Balance = balance
+		}
+		a.sorted = true
+	}
+	return a.postings
+}
+
+// IsProfitAndLoss reports whether the account appears on the Profit and Loss rather than the Balance Sheet
+func (a *LedgerAccount) IsProfitAndLoss() bool {
+	return a.Type.Class().IsProfitAndLoss()
+}
+
+// BalanceAt returns the balance of the account at the end of date. A zero date returns the closing balance
+func (a *LedgerAccount) BalanceAt(date time.Time) decimal.Decimal {
+	return a.BalanceBetween(time.Time{}, date)
+}
+
+// BalanceBetween returns the net movement of the account between fromDate and toDate inclusive.
+// Either date can be left empty to leave that end of the period open
+func (a *LedgerAccount) BalanceBetween(fromDate time.Time, toDate time.Time) decimal.Decimal {
+	balance := decimal.Zero
+	for _, posting := range a.Postings() {
+		if !fromDate.IsZero() && posting.Date.Before(dateOnly(fromDate)) {
+			continue
+		}
+		if !toDate.IsZero() && posting.Date.After(dateOnly(toDate)) {
+			break
+		}
+		balance = balance.Add(posting.Amount)
+	}
+	return balance
+}
+
+// TrackingBalancesAt returns the balance of the account per tracking option at the end of date.
+// Postings without tracking are left out
+func (a *LedgerAccount) TrackingBalancesAt(date time.Time) map[TrackingOption]decimal.Decimal {
+	balances := map[TrackingOption]decimal.Decimal{}
+	for _, posting := range a.Postings() {
+		if !date.IsZero() && posting.Date.After(dateOnly(date)) {
+			break
+		}
+		for _, option := range posting.Tracking {
+			balances[option] = balances[option].Add(posting.Amount)
+		}
+	}
+	return balances
+}
+
+// Ledger is a general ledger built locally from journals
+type Ledger struct {
+	accounts          map[string]*LedgerAccount
+	order             []*LedgerAccount
+	journals          map[string]bool
+	lastJournalNumber int
+}
+
+// NewLedger creates an empty ledger
+func NewLedger() *Ledger {
+	return &Ledger{
+		accounts: map[string]*LedgerAccount{},
+		order:    []*LedgerAccount{},
+		journals: map[string]bool{},
+	}
+}
+
+// BuildLedger builds a ledger from every journal of the organisation.
+// additional querystringParameters such as paymentsOnly can be added as a map
+func BuildLedger(ctx context.Context, provider xerogolang.IProvider, session goth.Session, querystringParameters map[string]string) (*Ledger, error) {
+	ledger := NewLedger()
+	err := ledger.Sync(ctx, provider, session, querystringParameters)
+	if err != nil {
+		return nil, err
+	}
+	return ledger, nil
+}
+
+// Sync streams the journals created since the last one added to the ledger
+func (l *Ledger) Sync(ctx context.Context, provider xerogolang.IProvider, session goth.Session, querystringParameters map[string]string) error {
+	return accounting.EachJournal(ctx, provider, session, l.lastJournalNumber, querystringParameters, func(journal accounting.Journal) error {
+		return l.Add(journal)
+	})
+}
+
+// Add posts journals to the ledger. Journals that were already added are skipped
+func (l *Ledger) Add(journals ...accounting.Journal) error {
+	for _, journal := range journals {
+		if journal.JournalID != "" && l.journals[journal.JournalID] {
+			continue
+		}
+		date, err := parseDate(journal.JournalDate)
+		if err != nil {
+			return fmt.Errorf("journal %d: %s", journal.JournalNumber, err.Error())
+		}
+
+		for _, line := range journal.JournalLines {
+			account, ok := l.accounts[line.AccountID]
+			if !ok {
+				account = &LedgerAccount{AccountID: line.AccountID}
+				l.accounts[line.AccountID] = account
+				l.order = append(l.order, account)
+			}
+			//later journals carry the current code and name of the account
+			account.Code = line.AccountCode
+			account.Name = line.AccountName
+			account.Type = line.AccountType
+
+			posting := Posting{
+				Date:          date,
+				JournalID:     journal.JournalID,
+				JournalNumber: journal.JournalNumber,
+				SourceID:      journal.SourceID,
+				SourceType:    journal.SourceType,
+				Description:   line.Description,
+				Amount:        line.NetAmount,
+				Tracking:      []TrackingOption{},
+			}
+			for _, tracking := range line.TrackingCategories {
+				posting.Tracking = append(posting.Tracking, TrackingOption{Category: tracking.Name, Option: tracking.Option})
+			}
+			account.postings = append(account.postings, posting)
+			account.sorted = false
+		}
+
+		if journal.JournalID != "" {
+			l.journals[journal.JournalID] = true
+		}
+		if journal.JournalNumber > l.lastJournalNumber {
+			l.lastJournalNumber = journal.JournalNumber
+		}
+	}
+	return nil
+}
+
+// LastJournalNumber returns the highest JournalNumber added, which is the offset to resume streaming from
+func (l *Ledger) LastJournalNumber() int {
+	return l.lastJournalNumber
+}
+
+// Accounts returns the accounts of the ledger in the order they were first posted to
+func (l *Ledger) Accounts() []*LedgerAccount {
+	return l.order
+}
+
+// Account returns an account by its AccountID
+func (l *Ledger) Account(accountID string) *LedgerAccount {
+	return l.accounts[accountID]
+}
+
+// BalanceAt returns the balance of an account at the end of date
+func (l *Ledger) BalanceAt(accountID string, date time.Time) decimal.Decimal {
+	account, ok := l.accounts[accountID]
+	if !ok {
+		return decimal.Zero
+	}
+	return account.BalanceAt(date)
+}
+
+// ReconcileOptions describe the trial balance the ledger is reconciled against
+type ReconcileOptions struct {
+	// Date of the trial balance
+	Date time.Time
+
+	// Start of the financial year holding Date. Profit and Loss accounts are reconciled from this date
+	// as the trial balance shows them year to date. When empty they are reconciled from the first journal
+	FinancialYearStart time.Time
+
+	// AccountID of the retained earnings account. Profit and Loss postings before FinancialYearStart are
+	// added to it, as Xero does when it closes a financial year
+	RetainedEarningsAccountID string
+}
+
+// LedgerDifference is an account whose ledger balance does not agree with the trial balance
+type LedgerDifference struct {
+	AccountID string
+	Code      string
+	Name      string
+
+	Ledger       decimal.Decimal
+	TrialBalance decimal.Decimal
+
+	// Difference is Ledger - TrialBalance
+	Difference decimal.Decimal
+}
+
+// Reconciliation is the result of comparing the ledger with a trial balance
+type Reconciliation struct {
+	Date        time.Time
+	Differences []LedgerDifference
+}
+
+// Balanced reports whether every account agrees
+func (r *Reconciliation) Balanced() bool {
+	return len(r.Differences) == 0
+}
+
+// ReconcileTrialBalance runs the trial balance at the date of options and reconciles the ledger against it
+func ReconcileTrialBalance(ctx context.Context, provider xerogolang.IProvider, session goth.Session, ledger *Ledger, options ReconcileOptions) (*Reconciliation, error) {
+	reports, err := accounting.RunTrialBalanceWithOptions(ctx, provider, session, &accounting.TrialBalanceOptions{
+		Date: options.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reports.Reports) == 0 {
+		return nil, errors.New("the trial balance returned no report")
+	}
+	return ledger.Reconcile(Parse(reports.Reports[0]), options)
+}
+
+// Reconcile compares the balances of the ledger with a parsed trial balance run at the date of options
+func (l *Ledger) Reconcile(report *Report, options ReconcileOptions) (*Reconciliation, error) {
+	accounts, err := TrialBalanceAccounts(report)
+	if err != nil {
+		return nil, err
+	}
+
+	computed := map[string]decimal.Decimal{}
+	for _, account := range l.order {
+		if account.IsProfitAndLoss() && !options.FinancialYearStart.IsZero() {
+			computed[account.AccountID] = computed[account.AccountID].Add(account.BalanceBetween(options.FinancialYearStart, options.Date))
+			if options.RetainedEarningsAccountID != "" {
+				previousYears := account.BalanceAt(options.FinancialYearStart.AddDate(0, 0, -1))
+				computed[options.RetainedEarningsAccountID] = computed[options.RetainedEarningsAccountID].Add(previousYears)
+			}
+			continue
+		}
+		computed[account.AccountID] = computed[account.AccountID].Add(account.BalanceAt(options.Date))
+	}
+
+	reconciliation := &Reconciliation{Date: options.Date, Differences: []LedgerDifference{}}
+	reported := map[string]bool{}
+	for _, account := range accounts {
+		reported[account.AccountID] = true
+		if difference := computed[account.AccountID].Sub(account.Balance); !difference.IsZero() {
+			reconciliation.Differences = append(reconciliation.Differences, LedgerDifference{
+				AccountID:    account.AccountID,
+				Code:         account.Code,
+				Name:         account.Name,
+				Ledger:       computed[account.AccountID],
+				TrialBalance: account.Balance,
+				Difference:   difference,
+			})
+		}
+	}
+
+	//accounts with a ledger balance that are missing from the trial balance
+	for _, account := range l.order {
+		if reported[account.AccountID] || computed[account.AccountID].IsZero() {
+			continue
+		}
+		reconciliation.Differences = append(reconciliation.Differences, LedgerDifference{
+			AccountID:  account.AccountID,
+			Code:       account.Code,
+			Name:       account.Name,
+			Ledger:     computed[account.AccountID],
+			Difference: computed[account.AccountID],
+		})
+	}
+	return reconciliation, nil
+}
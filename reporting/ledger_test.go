@@ -0,0 +1,73 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testJournal(number int, date string, amount int64, tracking string) accounting.Journal {
+	line := accounting.JournalLine{AccountID: "id-1", AccountCode: "200", AccountName: "Sales", AccountType: "REVENUE", NetAmount: decimal.NewFromInt(-amount)}
+	if tracking != "" {
+		line.TrackingCategories = []accounting.TrackingCategory{{Name: "Region", Option: tracking}}
+	}
+	return accounting.Journal{
+		JournalID:     "journal-" + date,
+		JournalNumber: number,
+		JournalDate:   testDate(date),
+		JournalLines: []accounting.JournalLine{
+			{AccountID: "id-0", AccountCode: "090", AccountName: "Bank", AccountType: "BANK", NetAmount: decimal.NewFromInt(amount)},
+			line,
+		},
+	}
+}
+
+func Test_Ledger(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	ledger := NewLedger()
+	a.NoError(ledger.Add(
+		testJournal(1, "2024-03-10", 100, "North"),
+		testJournal(2, "2023-12-01", 50, ""),
+		testJournal(3, "2024-04-05", 30, "South"),
+	))
+	//already added journals are skipped
+	a.NoError(ledger.Add(testJournal(1, "2024-03-10", 100, "North")))
+	a.Equal(3, ledger.LastJournalNumber())
+
+	bank := ledger.Account("id-0")
+	postings := bank.Postings()
+	a.Len(postings, 3)
+	a.Equal(2, postings[0].JournalNumber)
+	a.True(decimal.NewFromInt(150).Equal(postings[1].Balance))
+
+	a.True(decimal.NewFromInt(150).Equal(ledger.BalanceAt("id-0", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))))
+	a.True(decimal.NewFromInt(180).Equal(ledger.BalanceAt("id-0", time.Time{})))
+
+	tracking := ledger.Account("id-1").TrackingBalancesAt(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	a.Len(tracking, 1)
+	a.True(decimal.NewFromInt(-100).Equal(tracking[TrackingOption{Category: "Region", Option: "North"}]))
+
+	report := testTrialBalance(t,
+		[3]string{"Bank (090)", "150.00", ""},
+		[3]string{"Sales (200)", "", "100.00"},
+		[3]string{"Retained Earnings (960)", "", "50.00"},
+	)
+	options := ReconcileOptions{
+		Date:               time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		FinancialYearStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	reconciliation, err := ledger.Reconcile(report, options)
+	a.NoError(err)
+	a.Len(reconciliation.Differences, 1)
+	a.Equal("960", reconciliation.Differences[0].Code)
+
+	options.RetainedEarningsAccountID = "id-2"
+	reconciliation, err = ledger.Reconcile(report, options)
+	a.NoError(err)
+	a.True(reconciliation.Balanced())
+}
@@ -0,0 +1,657 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/omniboost/xerogolang/query"
+	"github.com/shopspring/decimal"
+)
+
+// Open item types of the receivables subledger
+const (
+	OpenItemInvoice     = "ACCREC"
+	OpenItemCreditNote  = "ACCRECCREDIT"
+	OpenItemOverpayment = "RECEIVE-OVERPAYMENT"
+	OpenItemPrepayment  = "RECEIVE-PREPAYMENT"
+)
+
+// AgingBucket is a range of days past the aging date of an open item
+type AgingBucket struct {
+	Label string
+
+	// MaxDays is the highest number of days the bucket holds. It is ignored when NoMax is set
+	MaxDays int
+	NoMax   bool
+}
+
+// NewAgingBuckets creates buckets from ascending day boundaries. NewAgingBuckets(0, 30, 60) gives
+// Current, 1 - 30 days, 31 - 60 days and Older
+func NewAgingBuckets(boundaries ...int) []AgingBucket {
+	buckets := []AgingBucket{}
+	from := 0
+	for n, boundary := range boundaries {
+		label := fmt.Sprintf("%d - %d days", from, boundary)
+		if n == 0 && boundary == 0 {
+			label = "Current"
+		} else if n == 0 {
+			label = fmt.Sprintf("Up to %d days", boundary)
+		}
+		buckets = append(buckets, AgingBucket{Label: label, MaxDays: boundary})
+		from = boundary + 1
+	}
+	return append(buckets, AgingBucket{Label: "Older", NoMax: true})
+}
+
+// DefaultAgingBuckets are Current, 1 - 30, 31 - 60, 61 - 90 days and Older
+var DefaultAgingBuckets = NewAgingBuckets(0, 30, 60, 90)
+
+// OpenItem is a receivables document with an amount outstanding at the as of date
+type OpenItem struct {
+	ContactID   string
+	ContactName string
+
+	// One of the OpenItem types
+	Type       string
+	DocumentID string
+	Number     string
+	Reference  string
+
+	Date    time.Time
+	DueDate time.Time
+
+	CurrencyCode string
+
+	// Total of the document in CurrencyCode - negative for credits
+	Total decimal.Decimal
+
+	// Outstanding at the as of date in CurrencyCode - negative for credits
+	Outstanding decimal.Decimal
+
+	// Days past the due date, or the document date when aging by date. Negative when not yet due
+	Days int
+
+	// Bucket is the index of the aging bucket holding the item
+	Bucket int
+}
+
+// ContactReceivables holds the open items of a contact in a single currency
+type ContactReceivables struct {
+	ContactID    string
+	Name         string
+	CurrencyCode string
+	Items        []OpenItem
+
+	// Buckets holds the outstanding amount per aging bucket
+	Buckets []decimal.Decimal
+	Total   decimal.Decimal
+}
+
+// Receivables is an open items subledger at a date
+type Receivables struct {
+	AsOf    time.Time
+	Buckets []AgingBucket
+
+	// Contacts ordered by name and currency
+	Contacts []*ContactReceivables
+
+	// Totals holds the outstanding amount per currency and aging bucket
+	Totals map[string][]decimal.Decimal
+}
+
+// Contact returns the receivables of a contact. Contacts with items in several currencies have one entry per currency
+func (r *Receivables) Contact(contactID string) []*ContactReceivables {
+	contacts := []*ContactReceivables{}
+	for _, contact := range r.Contacts {
+		if contact.ContactID == contactID {
+			contacts = append(contacts, contact)
+		}
+	}
+	return contacts
+}
+
+// ReceivablesData holds the documents a receivables subledger is built from. Allocations are only returned by Xero
+// when documents are fetched by page or by ID
+type ReceivablesData struct {
+	Invoices     []accounting.Invoice
+	CreditNotes  []accounting.CreditNote
+	Payments     []accounting.Payment
+	Overpayments []accounting.Overpayment
+	Prepayments  []accounting.Prepayment
+}
+
+// ReceivablesOptions control how the subledger is built
+type ReceivablesOptions struct {
+	// AsOf leaves out documents dated, and payments or allocations made, after this date. Defaults to today
+	AsOf time.Time
+
+	// Buckets used for aging - DefaultAgingBuckets when empty
+	Buckets []AgingBucket
+
+	// Age items by their document date instead of their due date
+	AgeByDate bool
+
+	// Only include documents in this currency
+	CurrencyCode string
+
+	// Convert every document to this currency using the document's CurrencyRate
+	BaseCurrency string
+}
+
+// RunReceivables fetches the receivables documents of the organisation and builds the open items subledger
+func RunReceivables(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options ReceivablesOptions) (*Receivables, error) {
+	data, err := FindReceivablesData(ctx, provider, session)
+	if err != nil {
+		return nil, err
+	}
+	return BuildReceivables(*data, options)
+}
+
+// FindReceivablesData pages through the authorised and paid receivables documents of the organisation
+func FindReceivablesData(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*ReceivablesData, error) {
+	return findReceivablesData(ctx, provider, session, "")
+}
+
+// FindContactReceivablesData pages through the authorised and paid receivables documents of a single contact.
+// Payments cannot be filtered by contact so every payment is returned
+func FindContactReceivablesData(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string) (*ReceivablesData, error) {
+	return findReceivablesData(ctx, provider, session, contactID)
+}
+
+func findReceivablesData(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string) (*ReceivablesData, error) {
+	data := &ReceivablesData{}
+	statuses := []string{"AUTHORISED", "PAID"}
+	var contactIDs []string
+	if contactID != "" {
+		contactIDs = []string{contactID}
+	}
+
+	for page := 1; ; page++ {
+		invoices, err := accounting.FindInvoicesWithOptions(ctx, provider, session, &accounting.InvoicesOptions{
+			ListOptions: accounting.ListOptions{Where: query.Invoice.Type.Eq(OpenItemInvoice), Page: page},
+			Statuses:    statuses,
+			ContactIDs:  contactIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.Invoices = append(data.Invoices, invoices.Invoices...)
+		if len(invoices.Invoices) < 100 {
+			break
+		}
+	}
+
+	where := func(typeField query.StringField, statusField query.StringField, contactField query.GUIDField, value string) query.Condition {
+		condition := query.And(typeField.Eq(value), statusField.In(statuses...))
+		if contactID != "" {
+			condition = condition.And(contactField.Eq(contactID))
+		}
+		return condition
+	}
+	for page := 1; ; page++ {
+		creditNotes, err := accounting.FindCreditNotesWithOptions(ctx, provider, session, &accounting.CreditNotesOptions{
+			ListOptions: accounting.ListOptions{Where: where(query.CreditNote.Type, query.CreditNote.Status, query.CreditNote.Contact.ContactID, OpenItemCreditNote), Page: page},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.CreditNotes = append(data.CreditNotes, creditNotes.CreditNotes...)
+		if len(creditNotes.CreditNotes) < 100 {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		overpayments, err := accounting.FindOverpaymentsWithOptions(ctx, provider, session, &accounting.OverpaymentsOptions{
+			ListOptions: accounting.ListOptions{Where: where(query.Overpayment.Type, query.Overpayment.Status, query.Overpayment.Contact.ContactID, OpenItemOverpayment), Page: page},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.Overpayments = append(data.Overpayments, overpayments.Overpayments...)
+		if len(overpayments.Overpayments) < 100 {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		prepayments, err := accounting.FindPrepaymentsWithOptions(ctx, provider, session, &accounting.PrepaymentsOptions{
+			ListOptions: accounting.ListOptions{Where: where(query.Prepayment.Type, query.Prepayment.Status, query.Prepayment.Contact.ContactID, OpenItemPrepayment), Page: page},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.Prepayments = append(data.Prepayments, prepayments.Prepayments...)
+		if len(prepayments.Prepayments) < 100 {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		payments, err := accounting.FindPaymentsWithOptions(ctx, provider, session, &accounting.PaymentsOptions{
+			ListOptions: accounting.ListOptions{Where: query.Payment.Status.Eq("AUTHORISED"), Page: page},
+		})
+		if err != nil {
+			return nil, err
+		}
+		data.Payments = append(data.Payments, payments.Payments...)
+		if len(payments.Payments) < 100 {
+			break
+		}
+	}
+	return data, nil
+}
+
+// BuildReceivables builds the open items subledger from receivables documents
+func BuildReceivables(data ReceivablesData, options ReceivablesOptions) (*Receivables, error) {
+	asOf := dateOnly(options.AsOf)
+	if options.AsOf.IsZero() {
+		asOf = dateOnly(time.Now())
+	}
+	buckets := options.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultAgingBuckets
+	}
+
+	//settled holds the amount paid, credited or refunded against each document up to the as of date
+	settled := map[string]decimal.Decimal{}
+	settle := func(documentID string, amount decimal.Decimal, date xerogolang.Date) error {
+		if documentID == "" {
+			return nil
+		}
+		d, err := parseDate(date)
+		if err != nil {
+			return err
+		}
+		if !d.After(asOf) {
+			settled[documentID] = settled[documentID].Add(amount)
+		}
+		return nil
+	}
+
+	payments := map[string]bool{}
+	addPayment := func(payment accounting.Payment, documentID string) error {
+		if payment.Status == "DELETED" || (payment.PaymentID != "" && payments[payment.PaymentID]) {
+			return nil
+		}
+		payments[payment.PaymentID] = true
+		return settle(documentID, payment.Amount, payment.Date)
+	}
+	//only payments on sales invoices and refunds of customer credit notes are matched on their document, refunds
+	//of overpayments and prepayments are settled with the overpayment below. Payments without a type are trusted
+	for _, payment := range data.Payments {
+		var err error
+		switch {
+		case payment.Invoice != nil && (payment.PaymentType == "" || payment.PaymentType == accounting.PaymentTypeAccRecPayment):
+			err = addPayment(payment, payment.Invoice.InvoiceID)
+		case payment.CreditNote != nil && (payment.PaymentType == "" || payment.PaymentType == accounting.PaymentTypeARCreditPayment):
+			err = addPayment(payment, payment.CreditNote.CreditNoteID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, invoice := range data.Invoices {
+		if invoice.Payments == nil {
+			continue
+		}
+		for _, payment := range *invoice.Payments {
+			if err := addPayment(payment, invoice.InvoiceID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	allocate := func(documentID string, allocations []accounting.Allocation) error {
+		for _, allocation := range allocations {
+			if err := settle(documentID, allocation.AppliedAmount, allocation.Date); err != nil {
+				return err
+			}
+			if err := settle(allocation.Invoice.InvoiceID, allocation.AppliedAmount, allocation.Date); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, creditNote := range data.CreditNotes {
+		if creditNote.Allocations != nil {
+			if err := allocate(creditNote.CreditNoteID, *creditNote.Allocations); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, overpayment := range data.Overpayments {
+		if err := allocate(overpayment.OverpaymentID, overpayment.Allocations); err != nil {
+			return nil, err
+		}
+		//payments on an overpayment are refunds
+		for _, payment := range overpayment.Payments {
+			if err := addPayment(payment, overpayment.OverpaymentID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, prepayment := range data.Prepayments {
+		if err := allocate(prepayment.PrepaymentID, prepayment.Allocations); err != nil {
+			return nil, err
+		}
+	}
+
+	items := []OpenItem{}
+	add := func(item OpenItem, status string, date xerogolang.Date, dueDate xerogolang.Date, rate decimal.Decimal) error {
+		if status != "AUTHORISED" && status != "PAID" {
+			return nil
+		}
+		if options.CurrencyCode != "" && item.CurrencyCode != options.CurrencyCode {
+			return nil
+		}
+		var err error
+		if item.Date, err = parseDate(date); err != nil {
+			return fmt.Errorf("%s %s: %s", item.Type, item.Number, err.Error())
+		}
+		if item.Date.After(asOf) {
+			return nil
+		}
+		item.DueDate = item.Date
+		if !dueDate.IsZero() {
+			if item.DueDate, err = parseDate(dueDate); err != nil {
+				return fmt.Errorf("%s %s: %s", item.Type, item.Number, err.Error())
+			}
+		}
+
+		outstanding := item.Total.Abs().Sub(settled[item.DocumentID])
+		if !outstanding.IsPositive() {
+			return nil
+		}
+		item.Outstanding = outstanding
+		if item.Type != OpenItemInvoice {
+			item.Total = item.Total.Abs().Neg()
+			item.Outstanding = item.Outstanding.Neg()
+		}
+
+		if options.BaseCurrency != "" && item.CurrencyCode == "" {
+			item.CurrencyCode = options.BaseCurrency
+		}
+		if options.BaseCurrency != "" && item.CurrencyCode != options.BaseCurrency {
+			if rate.IsZero() {
+				return fmt.Errorf("%s %s: no currency rate to convert %s to %s", item.Type, item.Number, item.CurrencyCode, options.BaseCurrency)
+			}
+			total, err := xerogolang.NewMoney(item.Total, item.CurrencyCode).Convert(options.BaseCurrency, rate)
+			if err != nil {
+				return fmt.Errorf("%s %s: %s", item.Type, item.Number, err.Error())
+			}
+			outstanding, err := xerogolang.NewMoney(item.Outstanding, item.CurrencyCode).Convert(options.BaseCurrency, rate)
+			if err != nil {
+				return fmt.Errorf("%s %s: %s", item.Type, item.Number, err.Error())
+			}
+			item.Total = total.Amount()
+			item.Outstanding = outstanding.Amount()
+			item.CurrencyCode = options.BaseCurrency
+		}
+
+		aged := item.DueDate
+		if options.AgeByDate {
+			aged = item.Date
+		}
+		item.Days = int(asOf.Sub(aged).Hours() / 24)
+		item.Bucket = len(buckets) - 1
+		for n, bucket := range buckets {
+			if bucket.NoMax || item.Days <= bucket.MaxDays {
+				item.Bucket = n
+				break
+			}
+		}
+		items = append(items, item)
+		return nil
+	}
+
+	for _, invoice := range data.Invoices {
+		if invoice.Type != OpenItemInvoice {
+			continue
+		}
+		err := add(OpenItem{
+			ContactID:    invoice.Contact.ContactID,
+			ContactName:  invoice.Contact.Name,
+			Type:         OpenItemInvoice,
+			DocumentID:   invoice.InvoiceID,
+			Number:       invoice.InvoiceNumber,
+			Reference:    invoice.Reference,
+			CurrencyCode: invoice.CurrencyCode,
+			Total:        invoice.Total,
+		}, string(invoice.Status), invoice.Date, invoice.DueDate, invoice.CurrencyRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, creditNote := range data.CreditNotes {
+		if creditNote.Type != OpenItemCreditNote {
+			continue
+		}
+		total := decimal.Zero
+		if creditNote.Total != nil {
+			total = *creditNote.Total
+		}
+		err := add(OpenItem{
+			ContactID:    creditNote.Contact.ContactID,
+			ContactName:  creditNote.Contact.Name,
+			Type:         OpenItemCreditNote,
+			DocumentID:   creditNote.CreditNoteID,
+			Number:       creditNote.CreditNoteNumber,
+			Reference:    creditNote.Reference,
+			CurrencyCode: creditNote.CurrencyCode,
+			Total:        total,
+		}, string(creditNote.Status), creditNote.Date, xerogolang.Date{}, creditNote.CurrencyRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, overpayment := range data.Overpayments {
+		if overpayment.Type != OpenItemOverpayment {
+			continue
+		}
+		err := add(OpenItem{
+			ContactID:    overpayment.Contact.ContactID,
+			ContactName:  overpayment.Contact.Name,
+			Type:         OpenItemOverpayment,
+			DocumentID:   overpayment.OverpaymentID,
+			CurrencyCode: overpayment.CurrencyCode,
+			Total:        overpayment.Total,
+		}, overpayment.Status, overpayment.Date, xerogolang.Date{}, overpayment.CurrencyRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, prepayment := range data.Prepayments {
+		if prepayment.Type != OpenItemPrepayment {
+			continue
+		}
+		err := add(OpenItem{
+			ContactID:    prepayment.Contact.ContactID,
+			ContactName:  prepayment.Contact.Name,
+			Type:         OpenItemPrepayment,
+			DocumentID:   prepayment.PrepaymentID,
+			CurrencyCode: prepayment.CurrencyCode,
+			Total:        prepayment.Total,
+		}, prepayment.Status, prepayment.Date, xerogolang.Date{}, prepayment.CurrencyRate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receivables := &Receivables{
+		AsOf:     asOf,
+		Buckets:  buckets,
+		Contacts: []*ContactReceivables{},
+		Totals:   map[string][]decimal.Decimal{},
+	}
+	contacts := map[string]*ContactReceivables{}
+	for _, item := range items {
+		key := item.ContactID + "|" + item.CurrencyCode
+		contact, ok := contacts[key]
+		if !ok {
+			contact = &ContactReceivables{
+				ContactID:    item.ContactID,
+				Name:         item.ContactName,
+				CurrencyCode: item.CurrencyCode,
+				Items:        []OpenItem{},
+				Buckets:      make([]decimal.Decimal, len(buckets)),
+			}
+			contacts[key] = contact
+			receivables.Contacts = append(receivables.Contacts, contact)
+		}
+		contact.Items = append(contact.Items, item)
+		contact.Buckets[item.Bucket] = contact.Buckets[item.Bucket].Add(item.Outstanding)
+		contact.Total = contact.Total.Add(item.Outstanding)
+
+		if _, ok := receivables.Totals[item.CurrencyCode]; !ok {
+			receivables.Totals[item.CurrencyCode] = make([]decimal.Decimal, len(buckets))
+		}
+		receivables.Totals[item.CurrencyCode][item.Bucket] = receivables.Totals[item.CurrencyCode][item.Bucket].Add(item.Outstanding)
+	}
+
+	for _, contact := range receivables.Contacts {
+		sort.SliceStable(contact.Items, func(i, j int) bool {
+			return contact.Items[i].Date.Before(contact.Items[j].Date)
+		})
+	}
+	sort.SliceStable(receivables.Contacts, func(i, j int) bool {
+		if receivables.Contacts[i].Name != receivables.Contacts[j].Name {
+			return receivables.Contacts[i].Name < receivables.Contacts[j].Name
+		}
+		return receivables.Contacts[i].CurrencyCode < receivables.Contacts[j].CurrencyCode
+	})
+	return receivables, nil
+}
+
+// ItemDifference is an open item whose outstanding amount does not agree with Xero's aged receivables report
+type ItemDifference struct {
+	Number string
+
+	Subledger decimal.Decimal
+	Xero      decimal.Decimal
+
+	// Difference is Subledger - Xero
+	Difference decimal.Decimal
+}
+
+// AgedReceivablesCheck compares the subledger of a contact with Xero's aged receivables report
+type AgedReceivablesCheck struct {
+	ContactID string
+
+	Subledger decimal.Decimal
+	Xero      decimal.Decimal
+
+	// Difference is Subledger - Xero
+	Difference decimal.Decimal
+
+	// Items that differ, matched on their number
+	Items []ItemDifference
+}
+
+// Agrees reports whether the subledger agrees with Xero
+func (c *AgedReceivablesCheck) Agrees() bool {
+	return c.Difference.IsZero() && len(c.Items) == 0
+}
+
+// CheckAgedReceivablesByContact runs Xero's aged receivables report for a contact at the as of date
+// of the subledger and compares it with the subledger. Build the subledger with a BaseCurrency to compare
+// contacts with foreign currency items
+func CheckAgedReceivablesByContact(ctx context.Context, provider xerogolang.IProvider, session goth.Session, receivables *Receivables, contactID string) (*AgedReceivablesCheck, error) {
+	reports, err := accounting.RunAgedReceivablesByContactWithOptions(ctx, provider, session, contactID, &accounting.AgedReportOptions{
+		Date: receivables.AsOf,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reports.Reports) == 0 {
+		return nil, errors.New("the aged receivables report returned no report")
+	}
+	return receivables.CheckAgedReceivables(contactID, Parse(reports.Reports[0]))
+}
+
+// agedReceivablesNumberColumns are the headings the document number can have in an aged receivables report
+var agedReceivablesNumberColumns = []string{"Reference", "Invoice Number", "Number"}
+
+// CheckAgedReceivables compares the subledger of a contact with a parsed aged receivables by contact report.
+// The amount due is read from the last column headed Due, or the last column when there is none, and the
+// document number from the Reference column
+func (r *Receivables) CheckAgedReceivables(contactID string, report *Report) (*AgedReceivablesCheck, error) {
+	dueColumn := ""
+	if len(report.Columns) > 0 {
+		dueColumn = report.Columns[len(report.Columns)-1]
+	}
+	numberColumn := ""
+	for _, column := range report.Columns {
+		if strings.HasPrefix(column, "Due") && column != "Due Date" {
+			dueColumn = column
+		}
+		if numberColumn == "" && containsString(agedReceivablesNumberColumns, column) {
+			numberColumn = column
+		}
+	}
+	if numberColumn == "" {
+		return nil, errors.New("the aged receivables report has no Reference, Invoice Number or Number column")
+	}
+	if dueColumn == "" || dueColumn == numberColumn {
+		return nil, errors.New("the aged receivables report has no amount due column")
+	}
+
+	check := &AgedReceivablesCheck{ContactID: contactID, Items: []ItemDifference{}}
+	//rows are matched on the exact document number, numbers are kept in report order so the differences come out the same every time
+	xero := map[string]decimal.Decimal{}
+	xeroNumbers := []string{}
+	for _, row := range report.Rows() {
+		if row.Type != RowTypeRow {
+			continue
+		}
+		due, ok := row.Cell(dueColumn)
+		if !ok || !due.IsNumeric {
+			continue
+		}
+		check.Xero = check.Xero.Add(due.Amount)
+		number := ""
+		if cell, ok := row.Cell(numberColumn); ok {
+			number = strings.TrimSpace(cell.Value)
+		}
+		if _, ok := xero[number]; !ok {
+			xeroNumbers = append(xeroNumbers, number)
+		}
+		xero[number] = xero[number].Add(due.Amount)
+	}
+
+	subledger := map[string]decimal.Decimal{}
+	subledgerNumbers := []string{}
+	for _, contact := range r.Contact(contactID) {
+		for _, item := range contact.Items {
+			check.Subledger = check.Subledger.Add(item.Outstanding)
+			if item.Number == "" {
+				continue
+			}
+			if _, ok := subledger[item.Number]; !ok {
+				subledgerNumbers = append(subledgerNumbers, item.Number)
+			}
+			subledger[item.Number] = subledger[item.Number].Add(item.Outstanding)
+		}
+	}
+
+	for _, number := range subledgerNumbers {
+		amount, ok := xero[number]
+		if ok && amount.Equal(subledger[number]) {
+			continue
+		}
+		check.Items = append(check.Items, ItemDifference{Number: number, Subledger: subledger[number], Xero: amount, Difference: subledger[number].Sub(amount)})
+	}
+	//rows without a number still count in the totals but cannot be matched
+	for _, number := range xeroNumbers {
+		if _, ok := subledger[number]; ok || number == "" {
+			continue
+		}
+		check.Items = append(check.Items, ItemDifference{Number: number, Xero: xero[number], Difference: xero[number].Neg()})
+	}
+	check.Difference = check.Subledger.Sub(check.Xero)
+	return check, nil
+}
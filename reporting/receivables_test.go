@@ -0,0 +1,133 @@
+package reporting
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testDate(value string) xerogolang.Date {
+	date, err := xerogolang.ParseDate(value)
+	if err != nil {
+		panic(err)
+	}
+	return date
+}
+
+func Test_NewAgingBuckets(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	buckets := NewAgingBuckets(0, 30, 60)
+	a.Len(buckets, 4)
+	a.Equal("Current", buckets[0].Label)
+	a.Equal("1 - 30 days", buckets[1].Label)
+	a.Equal("Older", buckets[3].Label)
+	a.True(buckets[3].NoMax)
+}
+
+func Test_BuildReceivables(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	contact := accounting.Contact{ContactID: "c1", Name: "Ridgeway University"}
+	credit := decimal.NewFromInt(40)
+	data := ReceivablesData{
+		Invoices: []accounting.Invoice{
+			{Type: "ACCREC", InvoiceID: "i1", InvoiceNumber: "INV-001", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-01-01T00:00:00"), DueDate: testDate("2024-01-31T00:00:00"), Total: decimal.NewFromInt(100)},
+			{Type: "ACCREC", InvoiceID: "i2", InvoiceNumber: "INV-002", Contact: contact, Status: "PAID", Date: testDate("2024-03-01T00:00:00"), DueDate: testDate("2024-03-31T00:00:00"), Total: decimal.NewFromInt(200), CurrencyCode: "USD", CurrencyRate: decimal.RequireFromString("0.5")},
+			{Type: "ACCREC", InvoiceID: "i3", InvoiceNumber: "INV-003", Contact: contact, Status: "DRAFT", Date: testDate("2024-03-01T00:00:00"), Total: decimal.NewFromInt(300)},
+			{Type: "ACCREC", InvoiceID: "i4", InvoiceNumber: "INV-004", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-05-01T00:00:00"), Total: decimal.NewFromInt(400)},
+		},
+		Payments: []accounting.Payment{
+			//paid after the as of date so INV-002 is still open
+			{PaymentID: "p1", Invoice: &accounting.Invoice{InvoiceID: "i2"}, Amount: decimal.NewFromInt(200), Date: testDate("2024-04-15T00:00:00"), Status: "AUTHORISED"},
+			{PaymentID: "p2", PaymentType: accounting.PaymentTypeAccRecPayment, Invoice: &accounting.Invoice{InvoiceID: "i1"}, Amount: decimal.NewFromInt(25), Date: testDate("/Date(1706659200000+0000)/"), Status: "AUTHORISED"},
+			//refunds and supplier payments are not settled against the invoice they point at
+			{PaymentID: "p3", PaymentType: accounting.PaymentTypeAROverpaymentPayment, Invoice: &accounting.Invoice{InvoiceID: "i1"}, Amount: decimal.NewFromInt(50), Date: testDate("2024-02-15T00:00:00"), Status: "AUTHORISED"},
+			{PaymentID: "p4", PaymentType: accounting.PaymentTypeAccPayPayment, Invoice: &accounting.Invoice{InvoiceID: "i1"}, Amount: decimal.NewFromInt(5), Date: testDate("2024-02-15T00:00:00"), Status: "AUTHORISED"},
+		},
+		CreditNotes: []accounting.CreditNote{
+			{Type: "ACCRECCREDIT", CreditNoteID: "cn1", CreditNoteNumber: "CN-001", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-02-01T00:00:00"), Total: &credit,
+				Allocations: &[]accounting.Allocation{{AppliedAmount: decimal.NewFromInt(15), Date: testDate("2024-02-01T00:00:00"), Invoice: accounting.InvoiceID{InvoiceID: "i1"}}}},
+		},
+	}
+
+	receivables, err := BuildReceivables(data, ReceivablesOptions{
+		AsOf:         time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		BaseCurrency: "NZD",
+	})
+	a.NoError(err)
+	a.Len(receivables.Contacts, 1)
+
+	ridgeway := receivables.Contact("c1")[0]
+	a.Len(ridgeway.Items, 3)
+	a.Equal("INV-001", ridgeway.Items[0].Number)
+	a.True(decimal.NewFromInt(60).Equal(ridgeway.Items[0].Outstanding))
+	a.Equal(60, ridgeway.Items[0].Days)
+	a.Equal(2, ridgeway.Items[0].Bucket)
+	a.True(decimal.NewFromInt(-25).Equal(ridgeway.Items[1].Outstanding))
+	a.Equal("INV-002", ridgeway.Items[2].Number)
+	a.True(decimal.NewFromInt(400).Equal(ridgeway.Items[2].Outstanding))
+	a.Equal(0, ridgeway.Items[2].Bucket)
+	a.True(decimal.NewFromInt(435).Equal(ridgeway.Total))
+
+	var reports accounting.Reports
+	a.NoError(json.Unmarshal([]byte(`{"Reports": [{"Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Date"}, {"Value": "Reference"}, {"Value": "Due Date"}, {"Value": "Due NZD"}]},
+		{"RowType": "Section", "Rows": [
+			{"RowType": "Row", "Cells": [{"Value": "2024-01-01"}, {"Value": "INV-001"}, {"Value": "2024-01-31"}, {"Value": "60.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-02-01"}, {"Value": "CN-001"}, {"Value": ""}, {"Value": "-25.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-03-01"}, {"Value": "INV-002"}, {"Value": "2024-03-31"}, {"Value": "390.00"}]}
+		]}
+	]}]}`), &reports))
+	report := ParseReports(&reports)[0]
+	check, err := receivables.CheckAgedReceivables("c1", report)
+	a.NoError(err)
+	a.False(check.Agrees())
+	a.True(decimal.NewFromInt(10).Equal(check.Difference))
+	a.Len(check.Items, 1)
+	a.Equal("INV-002", check.Items[0].Number)
+
+	//numbers are matched exactly and rows missing from either side are reported
+	a.NoError(json.Unmarshal([]byte(`{"Reports": [{"Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Date"}, {"Value": "Reference"}, {"Value": "Due Date"}, {"Value": "Due NZD"}]},
+		{"RowType": "Section", "Rows": [
+			{"RowType": "Row", "Cells": [{"Value": "2024-01-01"}, {"Value": "INV-0010"}, {"Value": "2024-01-31"}, {"Value": "60.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-02-01"}, {"Value": "CN-001"}, {"Value": ""}, {"Value": "-25.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-03-01"}, {"Value": "INV-002"}, {"Value": "2024-03-31"}, {"Value": "400.00"}]}
+		]}
+	]}]}`), &reports))
+	check, err = receivables.CheckAgedReceivables("c1", ParseReports(&reports)[0])
+	a.NoError(err)
+	a.True(check.Difference.IsZero())
+	a.Len(check.Items, 2)
+	a.Equal("INV-001", check.Items[0].Number)
+	a.True(decimal.NewFromInt(60).Equal(check.Items[0].Difference))
+	a.Equal("INV-0010", check.Items[1].Number)
+	a.True(decimal.NewFromInt(-60).Equal(check.Items[1].Difference))
+
+	//the columns are found by their heading wherever they are
+	a.NoError(json.Unmarshal([]byte(`{"Reports": [{"Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Date"}, {"Value": "Due Date"}, {"Value": "Invoice Number"}, {"Value": "Due NZD"}, {"Value": "Overdue"}]},
+		{"RowType": "Section", "Rows": [
+			{"RowType": "Row", "Cells": [{"Value": "2024-01-01"}, {"Value": "2024-01-31"}, {"Value": "INV-001"}, {"Value": "60.00"}, {"Value": "60.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-02-01"}, {"Value": ""}, {"Value": "CN-001"}, {"Value": "-25.00"}, {"Value": "0.00"}]},
+			{"RowType": "Row", "Cells": [{"Value": "2024-03-01"}, {"Value": "2024-03-31"}, {"Value": "INV-002"}, {"Value": "400.00"}, {"Value": "0.00"}]}
+		]}
+	]}]}`), &reports))
+	check, err = receivables.CheckAgedReceivables("c1", ParseReports(&reports)[0])
+	a.NoError(err)
+	a.True(check.Agrees())
+
+	a.NoError(json.Unmarshal([]byte(`{"Reports": [{"Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Date"}, {"Value": "Due NZD"}]}
+	]}]}`), &reports))
+	_, err = receivables.CheckAgedReceivables("c1", ParseReports(&reports)[0])
+	a.EqualError(err, "the aged receivables report has no Reference, Invoice Number or Number column")
+}
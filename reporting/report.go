@@ -0,0 +1,374 @@
+// Package reporting turns the Rows and Cells returned by the Reports endpoints into a
+// navigable tree of sections and rows with column headings and decimal values
+package reporting
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// RowType is the type of a row on a report
+type RowType string
+
+const (
+	// RowTypeHeader is the row holding the column headings
+	RowTypeHeader RowType = "Header"
+	// RowTypeSection groups rows under a title
+	RowTypeSection RowType = "Section"
+	// RowTypeRow is a detail row e.g. a single account
+	RowTypeRow RowType = "Row"
+	// RowTypeSummaryRow is the total of a section
+	RowTypeSummaryRow RowType = "SummaryRow"
+)
+
+// AttributeAccount is the ID of the cell attribute holding the AccountID of a row
+const AttributeAccount = "account"
+
+// Report is a parsed report
+type Report struct {
+	// The ID of the report
+	ID string
+
+	// The Name of the report e.g. ProfitAndLoss
+	Name string
+
+	// The type of report
+	Type string
+
+	// Titles shown above the report e.g. the organisation name and the period
+	Titles []string
+
+	// The date of the report
+	Date string
+
+	// Column headings taken from the Header row
+	Columns []string
+
+	// Sections of the report in the order returned by Xero
+	Sections []*Section
+
+	// The original report
+	Raw accounting.Report
+}
+
+// Section is a titled group of rows - the title is empty for sections that only hold totals
+type Section struct {
+	Title string
+
+	// Parent is nil for top level sections
+	Parent *Section
+
+	// Rows of the section, the SummaryRow is not included
+	Rows []*Row
+
+	// Summary is the SummaryRow of the section - nil when the section has no total
+	Summary *Row
+
+	// Sections nested within this section
+	Sections []*Section
+}
+
+// Path returns the titles of the section and its parents starting at the top level, skipping empty titles
+func (s *Section) Path() []string {
+	path := []string{}
+	for section := s; section != nil; section = section.Parent {
+		if section.Title != "" {
+			path = append([]string{section.Title}, path...)
+		}
+	}
+	return path
+}
+
+// Depth returns the nesting level of the section - 0 for top level sections
+func (s *Section) Depth() int {
+	depth := 0
+	for section := s.Parent; section != nil; section = section.Parent {
+		depth++
+	}
+	return depth
+}
+
+// Row is a Row or SummaryRow of a report
+type Row struct {
+	Type RowType
+
+	// Section holding the row
+	Section *Section
+
+	// Cells of the row, one for each column
+	Cells []Cell
+}
+
+// Label returns the value of the first cell of the row e.g. the account name
+func (r *Row) Label() string {
+	if len(r.Cells) == 0 {
+		return ""
+	}
+	return r.Cells[0].Value
+}
+
+// Cell returns the cell in the given column
+func (r *Row) Cell(column string) (Cell, bool) {
+	for _, cell := range r.Cells {
+		if cell.Column == column {
+			return cell, true
+		}
+	}
+	return Cell{}, false
+}
+
+// Value returns the decimal value of the cell in the given column
+func (r *Row) Value(column string) (decimal.Decimal, error) {
+	cell, ok := r.Cell(column)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("row %q has no column %q", r.Label(), column)
+	}
+	if !cell.IsNumeric {
+		return decimal.Zero, fmt.Errorf("row %q column %q holds %q which is not a number", r.Label(), column, cell.Value)
+	}
+	return cell.Amount, nil
+}
+
+// AccountID returns the AccountID attribute of the row - empty for rows that are not an account
+func (r *Row) AccountID() string {
+	for _, cell := range r.Cells {
+		if id := cell.Attributes[AttributeAccount]; id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// Cell is a single value on a report
+type Cell struct {
+	// Heading of the column holding the cell
+	Column string
+
+	// Value as returned by Xero
+	Value string
+
+	// Amount is the parsed Value - only set when IsNumeric is true
+	Amount decimal.Decimal
+
+	// IsNumeric is true when Value holds a number in a value column. Labels in the first column and identifiers
+	// such as a Reference of 0001 keep their text in Value and are never numeric
+	IsNumeric bool
+
+	// Attributes of the cell keyed by their Id e.g. account
+	Attributes map[string]string
+}
+
+// Parse builds a Report from a report returned by one of the Run functions in the accounting package
+func Parse(raw accounting.Report) *Report {
+	report := &Report{
+		ID:       raw.ReportID,
+		Name:     raw.ReportName,
+		Type:     raw.ReportType,
+		Date:     raw.ReportDate,
+		Columns:  []string{},
+		Sections: []*Section{},
+		Raw:      raw,
+	}
+	if raw.ReportTitles != nil {
+		report.Titles = *raw.ReportTitles
+	}
+	if raw.Rows == nil {
+		return report
+	}
+
+	for _, row := range *raw.Rows {
+		if RowType(row.RowType) == RowTypeHeader && row.Cells != nil {
+			for _, cell := range *row.Cells {
+				report.Columns = append(report.Columns, cell.Value)
+			}
+		}
+	}
+
+	//rows found outside of a section are collected in an untitled section
+	var loose *Section
+	for _, row := range *raw.Rows {
+		switch RowType(row.RowType) {
+		case RowTypeHeader:
+		case RowTypeSection:
+			report.Sections = append(report.Sections, report.parseSection(row, nil))
+			loose = nil
+		default:
+			if loose == nil {
+				loose = &Section{Rows: []*Row{}, Sections: []*Section{}}
+				report.Sections = append(report.Sections, loose)
+			}
+			loose.add(report.parseRow(row, loose))
+		}
+	}
+	return report
+}
+
+// ParseReports parses every report in a Reports collection
+func ParseReports(raw *accounting.Reports) []*Report {
+	reports := []*Report{}
+	if raw == nil {
+		return reports
+	}
+	for _, r := range raw.Reports {
+		reports = append(reports, Parse(r))
+	}
+	return reports
+}
+
+func (report *Report) parseSection(raw accounting.Row, parent *Section) *Section {
+	section := &Section{
+		Title:    raw.Title,
+		Parent:   parent,
+		Rows:     []*Row{},
+		Sections: []*Section{},
+	}
+	if raw.Rows == nil {
+		return section
+	}
+	for _, row := range *raw.Rows {
+		if RowType(row.RowType) == RowTypeSection {
+			section.Sections = append(section.Sections, report.parseSection(row, section))
+			continue
+		}
+		section.add(report.parseRow(row, section))
+	}
+	return section
+}
+
+func (s *Section) add(row *Row) {
+	if row.Type == RowTypeSummaryRow {
+		s.Summary = row
+		return
+	}
+	s.Rows = append(s.Rows, row)
+}
+
+func (report *Report) parseRow(raw accounting.Row, section *Section) *Row {
+	row := &Row{
+		Type:    RowType(raw.RowType),
+		Section: section,
+		Cells:   []Cell{},
+	}
+	if raw.Cells == nil {
+		return row
+	}
+	for n, rawCell := range *raw.Cells {
+		cell := Cell{
+			Value:      rawCell.Value,
+			Attributes: map[string]string{},
+		}
+		if n < len(report.Columns) {
+			cell.Column = report.Columns[n]
+		}
+		if report.isValueColumn(n) {
+			cell.Amount, cell.IsNumeric = ParseAmount(rawCell.Value)
+		}
+		if rawCell.Attributes != nil {
+			for _, attribute := range *rawCell.Attributes {
+				cell.Attributes[attribute.ID] = attribute.Value
+			}
+		}
+		row.Cells = append(row.Cells, cell)
+	}
+	return row
+}
+
+// identifierColumns are headings of columns that hold text even when it looks like a number e.g. a Reference of 0001
+var identifierColumns = []string{"Reference", "Invoice Number", "Number", "Code", "Account Code", "Account Number", "Tax Number", "Description"}
+
+// isValueColumn reports whether the cells of column n hold amounts. The first column holds the labels of
+// the rows and identifier columns hold text. Cells past the last heading are always parsed
+func (report *Report) isValueColumn(n int) bool {
+	if n >= len(report.Columns) {
+		return true
+	}
+	return n > 0 && !containsString(identifierColumns, report.Columns[n])
+}
+
+// ParseAmount parses a report value as a decimal. Thousands separators are ignored
+// and values in brackets are negative. ok is false when the value is not a number
+func ParseAmount(value string) (amount decimal.Decimal, ok bool) {
+	value = strings.TrimSpace(strings.ReplaceAll(value, ",", ""))
+	negative := strings.HasPrefix(value, "(") && strings.HasSuffix(value, ")")
+	if negative {
+		value = value[1 : len(value)-1]
+	}
+	if value == "" {
+		return decimal.Zero, false
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, false
+	}
+	if negative {
+		amount = amount.Neg()
+	}
+	return amount, true
+}
+
+// Rows returns every Row and SummaryRow of the report in order, depth first
+func (report *Report) Rows() []*Row {
+	rows := []*Row{}
+	var walk func(sections []*Section)
+	walk = func(sections []*Section) {
+		for _, section := range sections {
+			rows = append(rows, section.Rows...)
+			walk(section.Sections)
+			if section.Summary != nil {
+				rows = append(rows, section.Summary)
+			}
+		}
+	}
+	walk(report.Sections)
+	return rows
+}
+
+// AccountRows returns every row that carries an AccountID
+func (report *Report) AccountRows() []*Row {
+	rows := []*Row{}
+	for _, row := range report.Rows() {
+		if row.AccountID() != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Section returns the first section with the given title, searching nested sections too
+func (report *Report) Section(title string) *Section {
+	var find func(sections []*Section) *Section
+	find = func(sections []*Section) *Section {
+		for _, section := range sections {
+			if section.Title == title {
+				return section
+			}
+			if found := find(section.Sections); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return find(report.Sections)
+}
+
+// Row returns the first row whose label matches the given label
+func (report *Report) Row(label string) *Row {
+	for _, row := range report.Rows() {
+		if row.Label() == label {
+			return row
+		}
+	}
+	return nil
+}
+
+// Value returns the value of the row with the given label in the given column
+func (report *Report) Value(label string, column string) (decimal.Decimal, error) {
+	row := report.Row(label)
+	if row == nil {
+		return decimal.Zero, fmt.Errorf("report %q has no row %q", report.Name, label)
+	}
+	return row.Value(column)
+}
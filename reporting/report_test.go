@@ -0,0 +1,122 @@
+package reporting
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+const profitAndLossJSON = `{
+  "Reports": [{
+    "ReportID": "ProfitAndLoss",
+    "ReportName": "Profit and Loss",
+    "ReportType": "ProfitAndLoss",
+    "ReportTitles": ["Profit & Loss", "Vanderlay Industries", "1 March 2024 to 31 March 2024"],
+    "ReportDate": "18 April 2024",
+    "Rows": [
+      {"RowType": "Header", "Cells": [{"Value": ""}, {"Value": "31 Mar 24"}]},
+      {"RowType": "Section", "Title": "Income", "Rows": [
+        {"RowType": "Row", "Cells": [
+          {"Value": "Sales", "Attributes": [{"Value": "5040915e-8ce7-4177-8d08-fde416232f18", "Id": "account"}]},
+          {"Value": "1,250.00", "Attributes": [{"Value": "5040915e-8ce7-4177-8d08-fde416232f18", "Id": "account"}]}
+        ]},
+        {"RowType": "Row", "Cells": [
+          {"Value": "Interest Income", "Attributes": [{"Value": "e0a3b3bd-76a9-4b4a-9a8e-7b8f3c3f0c51", "Id": "account"}]},
+          {"Value": "(10.50)", "Attributes": [{"Value": "e0a3b3bd-76a9-4b4a-9a8e-7b8f3c3f0c51", "Id": "account"}]}
+        ]},
+        {"RowType": "SummaryRow", "Cells": [{"Value": "Total Income"}, {"Value": "1239.50"}]}
+      ]},
+      {"RowType": "Section", "Title": "", "Rows": [
+        {"RowType": "Row", "Cells": [{"Value": "Net Profit"}, {"Value": "1239.50"}]}
+      ]}
+    ]
+  }]
+}`
+
+func testProfitAndLoss(t *testing.T) *Report {
+	var reports accounting.Reports
+	if err := json.Unmarshal([]byte(profitAndLossJSON), &reports); err != nil {
+		t.Fatal(err)
+	}
+	return ParseReports(&reports)[0]
+}
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	report := testProfitAndLoss(t)
+	a.Equal([]string{"", "31 Mar 24"}, report.Columns)
+	a.Len(report.Sections, 2)
+
+	income := report.Section("Income")
+	a.NotNil(income)
+	a.Len(income.Rows, 2)
+	a.Equal("Total Income", income.Summary.Label())
+	a.Equal([]string{"Income"}, income.Path())
+
+	value, err := report.Value("Sales", "31 Mar 24")
+	a.NoError(err)
+	a.True(decimal.NewFromFloat(1250).Equal(value))
+
+	value, err = report.Value("Interest Income", "31 Mar 24")
+	a.NoError(err)
+	a.True(decimal.NewFromFloat(-10.5).Equal(value))
+
+	_, err = report.Value("Sales", "30 Apr 24")
+	a.Error(err)
+
+	accounts := report.AccountRows()
+	a.Len(accounts, 2)
+	a.Equal("5040915e-8ce7-4177-8d08-fde416232f18", accounts[0].AccountID())
+
+	rows := report.Rows()
+	a.Len(rows, 4)
+	a.Equal("Net Profit", rows[3].Label())
+}
+
+func Test_ParseAmount(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	amount, ok := ParseAmount("-1,000.25")
+	a.True(ok)
+	a.Equal("-1000.25", amount.String())
+
+	_, ok = ParseAmount("31 Mar 24")
+	a.False(ok)
+
+	_, ok = ParseAmount("")
+	a.False(ok)
+}
+
+func Test_ParseIdentifierColumns(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	var reports accounting.Reports
+	a.NoError(json.Unmarshal([]byte(`{"Reports": [{"Rows": [
+		{"RowType": "Header", "Cells": [{"Value": "Account"}, {"Value": "Reference"}, {"Value": "Due NZD"}]},
+		{"RowType": "Section", "Rows": [
+			{"RowType": "Row", "Cells": [{"Value": "2024"}, {"Value": "0001"}, {"Value": "60.00"}, {"Value": "5"}]}
+		]}
+	]}]}`), &reports))
+	row := ParseReports(&reports)[0].Rows()[0]
+
+	//labels and identifiers keep their text and are not amounts
+	a.False(row.Cells[0].IsNumeric)
+	reference, ok := row.Cell("Reference")
+	a.True(ok)
+	a.Equal("0001", reference.Value)
+	a.False(reference.IsNumeric)
+	_, err := row.Value("Reference")
+	a.EqualError(err, `row "2024" column "Reference" holds "0001" which is not a number`)
+
+	value, err := row.Value("Due NZD")
+	a.NoError(err)
+	a.Equal("60", value.String())
+	a.True(row.Cells[3].IsNumeric)
+}
@@ -0,0 +1,515 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// statementDateFormat is how dates are shown on rendered statements
+const statementDateFormat = "2 Jan 2006"
+
+// StatementType is the style of a customer statement
+type StatementType string
+
+// Statement types
+const (
+	// StatementActivity lists every transaction in the date range between an opening and a closing balance
+	StatementActivity StatementType = "ACTIVITY"
+
+	// StatementOutstanding lists the documents still open at the end of the date range
+	StatementOutstanding StatementType = "OUTSTANDING"
+)
+
+// StatementOptions control the content of a statement
+type StatementOptions struct {
+	// Style of the statement - defaults to an activity statement
+	Type StatementType
+
+	// Start of the date range - only used by activity statements
+	FromDate time.Time
+
+	// End of the date range - defaults to today
+	ToDate time.Time
+
+	// Only include documents in this currency - defaults to the DefaultCurrency of the contact, or the currency
+	// of its documents when they are all in one
+	CurrencyCode string
+
+	// BrandingThemeID of the theme named on the statement - the default theme when empty. Only used by RunStatement
+	BrandingThemeID string
+}
+
+// StatementLine is a single transaction or open document on a statement
+type StatementLine struct {
+	Date time.Time
+
+	// Activity describes the line e.g. Invoice, Credit Note or Payment
+	Activity  string
+	Number    string
+	Reference string
+
+	// DueDate of invoices - zero for other lines
+	DueDate time.Time
+
+	// Amount is positive for amounts the contact owes and negative for payments and credits
+	Amount decimal.Decimal
+
+	// Balance is the running balance after this line
+	Balance decimal.Decimal
+}
+
+// Statement is a statement of account for a single contact
+type Statement struct {
+	Type         StatementType
+	FromDate     time.Time
+	ToDate       time.Time
+	CurrencyCode string
+
+	OrganisationName string
+	BrandingTheme    string
+	Contact          accounting.Contact
+
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+
+	Lines []StatementLine
+}
+
+// Title returns the heading of the statement
+func (s *Statement) Title() string {
+	if s.Type == StatementOutstanding {
+		return "Outstanding Statement"
+	}
+	return "Activity Statement"
+}
+
+// Period returns the date range of the statement as shown on it
+func (s *Statement) Period() string {
+	if s.Type == StatementOutstanding || s.FromDate.IsZero() {
+		return "As at " + s.ToDate.Format(statementDateFormat)
+	}
+	return s.FromDate.Format(statementDateFormat) + " to " + s.ToDate.Format(statementDateFormat)
+}
+
+// Address returns the lines of the contact's postal address, or its street address when it has no postal address
+func (s *Statement) Address() []string {
+	if s.Contact.Addresses == nil {
+		return []string{}
+	}
+	var address *accounting.Address
+	for n, a := range *s.Contact.Addresses {
+		if a.AddressType == "POBOX" || (address == nil && a.AddressType == "STREET") {
+			address = &(*s.Contact.Addresses)[n]
+		}
+	}
+	if address == nil {
+		return []string{}
+	}
+
+	lines := []string{}
+	if address.AttentionTo != "" {
+		lines = append(lines, "Attention: "+address.AttentionTo)
+	}
+	for _, line := range []string{address.AddressLine1, address.AddressLine2, address.AddressLine3, address.AddressLine4} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if town := strings.TrimSpace(strings.Join([]string{address.City, address.Region, address.PostalCode}, " ")); town != "" {
+		lines = append(lines, strings.Join(strings.Fields(town), " "))
+	}
+	if address.Country != "" {
+		lines = append(lines, address.Country)
+	}
+	return lines
+}
+
+// RunStatement fetches a contact, its receivables documents, the organisation and its branding theme and builds a statement
+func RunStatement(ctx context.Context, provider xerogolang.IProvider, session goth.Session, contactID string, options StatementOptions) (*Statement, error) {
+	contacts, err := accounting.FindContact(ctx, provider, session, contactID)
+	if err != nil {
+		return nil, err
+	}
+	if len(contacts.Contacts) == 0 {
+		return nil, errors.New("contact " + contactID + " was not found")
+	}
+	data, err := FindContactReceivablesData(ctx, provider, session, contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	statement, err := BuildStatement(contacts.Contacts[0], *data, options)
+	if err != nil {
+		return nil, err
+	}
+
+	organisations, err := accounting.FindOrganisation(ctx, provider, session)
+	if err != nil {
+		return nil, err
+	}
+	if len(organisations.Organisations) > 0 {
+		statement.OrganisationName = organisations.Organisations[0].Name
+	}
+
+	themes, err := accounting.FindBrandingThemes(ctx, provider, session)
+	if err != nil {
+		return nil, err
+	}
+	for _, theme := range themes.BrandingThemes {
+		if theme.BrandingThemeID == options.BrandingThemeID || (options.BrandingThemeID == "" && theme.SortOrder == 0) {
+			statement.BrandingTheme = theme.Name
+			break
+		}
+	}
+	return statement, nil
+}
+
+// BuildStatement builds the statement of a contact from its receivables documents
+func BuildStatement(contact accounting.Contact, data ReceivablesData, options StatementOptions) (*Statement, error) {
+	if options.CurrencyCode == "" {
+		currencyCode, err := statementCurrency(contact, data)
+		if err != nil {
+			return nil, err
+		}
+		options.CurrencyCode = currencyCode
+	}
+	statement := &Statement{
+		Type:         options.Type,
+		FromDate:     dateOnly(options.FromDate),
+		ToDate:       dateOnly(options.ToDate),
+		CurrencyCode: options.CurrencyCode,
+		Contact:      contact,
+		Lines:        []StatementLine{},
+	}
+	if statement.Type == "" {
+		statement.Type = StatementActivity
+	}
+	if options.FromDate.IsZero() {
+		statement.FromDate = time.Time{}
+	}
+	if options.ToDate.IsZero() {
+		statement.ToDate = dateOnly(time.Now())
+	}
+
+	switch statement.Type {
+	case StatementOutstanding:
+		return statement, statement.addOutstanding(data)
+	case StatementActivity:
+		return statement, statement.addActivity(data)
+	}
+	return nil, fmt.Errorf("statement type must be ACTIVITY or OUTSTANDING, not %q", string(statement.Type))
+}
+
+func (s *Statement) addOutstanding(data ReceivablesData) error {
+	receivables, err := BuildReceivables(data, ReceivablesOptions{AsOf: s.ToDate, CurrencyCode: s.CurrencyCode})
+	if err != nil {
+		return err
+	}
+	activities := map[string]string{
+		OpenItemInvoice:     "Invoice",
+		OpenItemCreditNote:  "Credit Note",
+		OpenItemOverpayment: "Overpayment",
+		OpenItemPrepayment:  "Prepayment",
+	}
+	for _, contact := range receivables.Contact(s.Contact.ContactID) {
+		for _, item := range contact.Items {
+			line := StatementLine{
+				Date:      item.Date,
+				Activity:  activities[item.Type],
+				Number:    item.Number,
+				Reference: item.Reference,
+				Amount:    item.Outstanding,
+			}
+			if item.Type == OpenItemInvoice {
+				line.DueDate = item.DueDate
+			}
+			s.Lines = append(s.Lines, line)
+		}
+	}
+	s.balance()
+	return nil
+}
+
+// statementCurrency returns the currency of a statement without a CurrencyCode option. Amounts in different
+// currencies cannot share a balance, so it fails when the contact has no DefaultCurrency and its documents
+// are in more than one currency
+func statementCurrency(contact accounting.Contact, data ReceivablesData) (string, error) {
+	if contact.DefaultCurrency != "" {
+		return contact.DefaultCurrency, nil
+	}
+	currencies := []string{}
+	found := func(currencyCode string) {
+		if !containsString(currencies, currencyCode) {
+			currencies = append(currencies, currencyCode)
+		}
+	}
+	for _, invoice := range data.Invoices {
+		if invoice.Type == OpenItemInvoice && invoice.Contact.ContactID == contact.ContactID {
+			found(invoice.CurrencyCode)
+		}
+	}
+	for _, creditNote := range data.CreditNotes {
+		if creditNote.Type == OpenItemCreditNote && creditNote.Contact.ContactID == contact.ContactID {
+			found(creditNote.CurrencyCode)
+		}
+	}
+	for _, overpayment := range data.Overpayments {
+		if overpayment.Type == OpenItemOverpayment && overpayment.Contact.ContactID == contact.ContactID {
+			found(overpayment.CurrencyCode)
+		}
+	}
+	for _, prepayment := range data.Prepayments {
+		if prepayment.Type == OpenItemPrepayment && prepayment.Contact.ContactID == contact.ContactID {
+			found(prepayment.CurrencyCode)
+		}
+	}
+	if len(currencies) > 1 {
+		sort.Strings(currencies)
+		return "", fmt.Errorf("contact %s has documents in %s - set the CurrencyCode of the statement", contact.Name, strings.Join(currencies, ", "))
+	}
+	if len(currencies) == 1 {
+		return currencies[0], nil
+	}
+	return "", nil
+}
+
+// statementActivityOrder orders the lines of a statement that are on the same day
+var statementActivityOrder = map[string]int{"Invoice": 0, "Credit Note": 1, "Overpayment": 2, "Prepayment": 3, "Payment": 4, "Refund": 5}
+
+func (s *Statement) addActivity(data ReceivablesData) error {
+	lines := []StatementLine{}
+	add := func(date xerogolang.Date, currencyCode string, line StatementLine) error {
+		if s.CurrencyCode != "" && currencyCode != s.CurrencyCode {
+			return nil
+		}
+		var err error
+		if line.Date, err = parseDate(date); err != nil {
+			return fmt.Errorf("%s %s: %s", line.Activity, line.Number, err.Error())
+		}
+		if !line.Date.After(s.ToDate) {
+			lines = append(lines, line)
+		}
+		return nil
+	}
+	posted := func(status string) bool {
+		return status == "AUTHORISED" || status == "PAID"
+	}
+
+	invoices := map[string]accounting.Invoice{}
+	for _, invoice := range data.Invoices {
+		if invoice.Type != OpenItemInvoice || invoice.Contact.ContactID != s.Contact.ContactID || !posted(string(invoice.Status)) {
+			continue
+		}
+		invoices[invoice.InvoiceID] = invoice
+		line := StatementLine{Activity: "Invoice", Number: invoice.InvoiceNumber, Reference: invoice.Reference, Amount: invoice.Total}
+		if !invoice.DueDate.IsZero() {
+			dueDate, err := parseDate(invoice.DueDate)
+			if err != nil {
+				return fmt.Errorf("invoice %s: %s", invoice.InvoiceNumber, err.Error())
+			}
+			line.DueDate = dueDate
+		}
+		if err := add(invoice.Date, invoice.CurrencyCode, line); err != nil {
+			return err
+		}
+	}
+
+	creditNotes := map[string]accounting.CreditNote{}
+	for _, creditNote := range data.CreditNotes {
+		if creditNote.Type != OpenItemCreditNote || creditNote.Contact.ContactID != s.Contact.ContactID || !posted(string(creditNote.Status)) || creditNote.Total == nil {
+			continue
+		}
+		creditNotes[creditNote.CreditNoteID] = creditNote
+		line := StatementLine{Activity: "Credit Note", Number: creditNote.CreditNoteNumber, Reference: creditNote.Reference, Amount: creditNote.Total.Neg()}
+		if err := add(creditNote.Date, creditNote.CurrencyCode, line); err != nil {
+			return err
+		}
+	}
+
+	payments := map[string]bool{}
+	addPayment := func(payment accounting.Payment, activity string, number string, currencyCode string, amount decimal.Decimal) error {
+		if payment.Status == "DELETED" || (payment.PaymentID != "" && payments[payment.PaymentID]) {
+			return nil
+		}
+		payments[payment.PaymentID] = true
+		return add(payment.Date, currencyCode, StatementLine{Activity: activity, Number: number, Reference: payment.Reference, Amount: amount})
+	}
+	for _, payment := range data.Payments {
+		if payment.Invoice != nil {
+			if invoice, ok := invoices[payment.Invoice.InvoiceID]; ok {
+				if err := addPayment(payment, "Payment", invoice.InvoiceNumber, invoice.CurrencyCode, payment.Amount.Neg()); err != nil {
+					return err
+				}
+			}
+		} else if payment.CreditNote != nil {
+			if creditNote, ok := creditNotes[payment.CreditNote.CreditNoteID]; ok {
+				if err := addPayment(payment, "Refund", creditNote.CreditNoteNumber, creditNote.CurrencyCode, payment.Amount); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, invoice := range data.Invoices {
+		if _, ok := invoices[invoice.InvoiceID]; !ok || invoice.Payments == nil {
+			continue
+		}
+		for _, payment := range *invoice.Payments {
+			if err := addPayment(payment, "Payment", invoice.InvoiceNumber, invoice.CurrencyCode, payment.Amount.Neg()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, overpayment := range data.Overpayments {
+		if overpayment.Type != OpenItemOverpayment || overpayment.Contact.ContactID != s.Contact.ContactID || !posted(overpayment.Status) {
+			continue
+		}
+		if err := add(overpayment.Date, overpayment.CurrencyCode, StatementLine{Activity: "Overpayment", Amount: overpayment.Total.Neg()}); err != nil {
+			return err
+		}
+		for _, payment := range overpayment.Payments {
+			if err := addPayment(payment, "Refund", "", overpayment.CurrencyCode, payment.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	for _, prepayment := range data.Prepayments {
+		if prepayment.Type != OpenItemPrepayment || prepayment.Contact.ContactID != s.Contact.ContactID || !posted(prepayment.Status) {
+			continue
+		}
+		if err := add(prepayment.Date, prepayment.CurrencyCode, StatementLine{Activity: "Prepayment", Amount: prepayment.Total.Neg()}); err != nil {
+			return err
+		}
+	}
+
+	//lines on the same day are ordered by activity then number so a statement is the same every time it is run
+	sort.SliceStable(lines, func(i, j int) bool {
+		if !lines[i].Date.Equal(lines[j].Date) {
+			return lines[i].Date.Before(lines[j].Date)
+		}
+		if statementActivityOrder[lines[i].Activity] != statementActivityOrder[lines[j].Activity] {
+			return statementActivityOrder[lines[i].Activity] < statementActivityOrder[lines[j].Activity]
+		}
+		return lines[i].Number < lines[j].Number
+	})
+	for _, line := range lines {
+		if !s.FromDate.IsZero() && line.Date.Before(s.FromDate) {
+			s.OpeningBalance = s.OpeningBalance.Add(line.Amount)
+			continue
+		}
+		s.Lines = append(s.Lines, line)
+	}
+	s.balance()
+	return nil
+}
+
+// balance sets the running balances and the closing balance from the opening balance
+func (s *Statement) balance() {
+	balance := s.OpeningBalance
+	for n := range s.Lines {
+		balance = balance.Add(s.Lines[n].Amount)
+		s.Lines[n].Balance = balance
+	}
+	s.ClosingBalance = balance
+}
+
+// WriteText renders the statement as plain text
+func (s *Statement) WriteText(w io.Writer) error {
+	header := []string{}
+	if s.OrganisationName != "" {
+		header = append(header, s.OrganisationName)
+	}
+	header = append(header, s.Title(), s.Period(), "")
+	header = append(header, s.Contact.Name)
+	header = append(header, s.Address()...)
+	if s.CurrencyCode != "" {
+		header = append(header, "", "Amounts in "+s.CurrencyCode)
+	}
+	if _, err := io.WriteString(w, strings.Join(header, "\n")+"\n\n"); err != nil {
+		return err
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(table, "Date\tActivity\tNumber\tReference\tDue Date\tAmount\tBalance\t")
+	if s.Type == StatementActivity {
+		fmt.Fprintf(table, "%s\tOpening Balance\t\t\t\t\t%s\t\n", s.FormatDate(s.FromDate), s.FormatAmount(s.OpeningBalance))
+	}
+	for _, line := range s.Lines {
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", s.FormatDate(line.Date), line.Activity, line.Number, line.Reference,
+			s.FormatDate(line.DueDate), s.FormatAmount(line.Amount), s.FormatAmount(line.Balance))
+	}
+	fmt.Fprintf(table, "%s\tClosing Balance\t\t\t\t\t%s\t\n", s.FormatDate(s.ToDate), s.FormatAmount(s.ClosingBalance))
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	if s.BrandingTheme != "" {
+		_, err := io.WriteString(w, "\n"+s.BrandingTheme+"\n")
+		return err
+	}
+	return nil
+}
+
+// FormatDate formats a date the way it is shown on the statement - zero dates are left empty
+func (s *Statement) FormatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format(statementDateFormat)
+}
+
+// FormatAmount formats an amount to the minor units of the currency of the statement, e.g. whole yen for JPY
+func (s *Statement) FormatAmount(amount decimal.Decimal) string {
+	return amount.StringFixed(xerogolang.MinorUnits(s.CurrencyCode))
+}
+
+// WriteHTML renders the statement as an HTML document
+func (s *Statement) WriteHTML(w io.Writer) error {
+	return statementTemplate.Execute(w, s)
+}
+
+var statementTemplate = template.Must(template.New("statement").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - {{.Contact.Name}}</title>
+</head>
+<body class="statement"{{if .BrandingTheme}} data-branding-theme="{{.BrandingTheme}}"{{end}}>
+<header>
+{{if .OrganisationName}}<h1>{{.OrganisationName}}</h1>
+{{end}}<h2>{{.Title}}</h2>
+<p class="period">{{.Period}}</p>
+<address>
+<strong>{{.Contact.Name}}</strong>{{range .Address}}<br>
+{{.}}{{end}}
+</address>
+{{if .CurrencyCode}}<p class="currency">Amounts in {{.CurrencyCode}}</p>
+{{end}}</header>
+<table>
+<thead>
+<tr><th>Date</th><th>Activity</th><th>Number</th><th>Reference</th><th>Due Date</th><th>Amount</th><th>Balance</th></tr>
+</thead>
+<tbody>
+{{if eq .Type "ACTIVITY"}}<tr class="opening"><td>{{$.FormatDate .FromDate}}</td><td colspan="5">Opening Balance</td><td>{{$.FormatAmount .OpeningBalance}}</td></tr>
+{{end}}{{range .Lines}}<tr><td>{{$.FormatDate .Date}}</td><td>{{.Activity}}</td><td>{{.Number}}</td><td>{{.Reference}}</td><td>{{$.FormatDate .DueDate}}</td><td>{{$.FormatAmount .Amount}}</td><td>{{$.FormatAmount .Balance}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+<tr class="closing"><td>{{.FormatDate .ToDate}}</td><td colspan="5">Closing Balance</td><td>{{$.FormatAmount .ClosingBalance}}</td></tr>
+</tfoot>
+</table>
+{{if .BrandingTheme}}<footer>{{.BrandingTheme}}</footer>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,116 @@
+package reporting
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStatementData() (accounting.Contact, ReceivablesData) {
+	contact := accounting.Contact{
+		ContactID: "c1",
+		Name:      "Ridgeway University",
+		Addresses: &[]accounting.Address{
+			{AddressType: "STREET", AddressLine1: "1 Campus Drive"},
+			{AddressType: "POBOX", AddressLine1: "PO Box 8", City: "Ridgeway", PostalCode: "1234", Country: "New Zealand"},
+		},
+	}
+	credit := decimal.NewFromInt(30)
+	return contact, ReceivablesData{
+		Invoices: []accounting.Invoice{
+			{Type: "ACCREC", InvoiceID: "i1", InvoiceNumber: "INV-001", Contact: contact, Status: "PAID", Date: testDate("2024-02-10T00:00:00"), DueDate: testDate("2024-02-28T00:00:00"), Total: decimal.NewFromInt(100)},
+			{Type: "ACCREC", InvoiceID: "i2", InvoiceNumber: "INV-002", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-03-05T00:00:00"), DueDate: testDate("2024-03-31T00:00:00"), Total: decimal.NewFromInt(250)},
+			{Type: "ACCREC", InvoiceID: "i3", InvoiceNumber: "INV-003", Contact: accounting.Contact{ContactID: "c2"}, Status: "AUTHORISED", Date: testDate("2024-03-05T00:00:00"), Total: decimal.NewFromInt(999)},
+		},
+		Payments: []accounting.Payment{
+			{PaymentID: "p1", Invoice: &accounting.Invoice{InvoiceID: "i1"}, Amount: decimal.NewFromInt(100), Date: testDate("2024-03-01T00:00:00"), Reference: "DD", Status: "AUTHORISED"},
+		},
+		CreditNotes: []accounting.CreditNote{
+			{Type: "ACCRECCREDIT", CreditNoteID: "cn1", CreditNoteNumber: "CN-001", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-03-10T00:00:00"), Total: &credit},
+		},
+	}
+}
+
+func Test_BuildStatement(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	contact, data := testStatementData()
+	statement, err := BuildStatement(contact, data, StatementOptions{
+		FromDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	a.NoError(err)
+	a.True(decimal.NewFromInt(100).Equal(statement.OpeningBalance))
+	a.Len(statement.Lines, 3)
+	a.Equal("Payment", statement.Lines[0].Activity)
+	a.True(statement.Lines[0].Balance.IsZero())
+	a.True(decimal.NewFromInt(220).Equal(statement.ClosingBalance))
+	a.Equal([]string{"PO Box 8", "Ridgeway 1234", "New Zealand"}, statement.Address())
+
+	statement.OrganisationName = "Demo Company"
+	statement.BrandingTheme = "Standard"
+	text := &bytes.Buffer{}
+	a.NoError(statement.WriteText(text))
+	a.Contains(text.String(), "Activity Statement\n1 Mar 2024 to 31 Mar 2024")
+	a.Contains(text.String(), "Closing Balance")
+	a.Contains(text.String(), "220.00")
+
+	html := &bytes.Buffer{}
+	a.NoError(statement.WriteHTML(html))
+	a.Contains(html.String(), `<td>CN-001</td>`)
+	a.Contains(html.String(), `data-branding-theme="Standard"`)
+
+	//lines on the same day come out in the same order whatever order the documents are in
+	payment := accounting.Payment{PaymentID: "p2", Amount: decimal.NewFromInt(50), Date: testDate("2024-03-05T00:00:00"), Status: "AUTHORISED"}
+	data.Invoices = append(data.Invoices,
+		accounting.Invoice{Type: "ACCREC", InvoiceID: "i4", InvoiceNumber: "INV-004", Contact: contact, Status: "AUTHORISED", Date: testDate("2024-03-05T00:00:00"), Total: decimal.NewFromInt(50), Payments: &[]accounting.Payment{payment}})
+	data.Invoices[1], data.Invoices[3] = data.Invoices[3], data.Invoices[1]
+	statement, err = BuildStatement(contact, data, StatementOptions{
+		FromDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:   time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	a.NoError(err)
+	numbers := []string{}
+	for _, line := range statement.Lines {
+		numbers = append(numbers, line.Activity+" "+line.Number)
+	}
+	a.Equal([]string{"Payment INV-001", "Invoice INV-002", "Invoice INV-004", "Payment INV-004", "Credit Note CN-001"}, numbers)
+
+	//amounts are shown in the minor units of the currency
+	yen := &Statement{Type: StatementOutstanding, CurrencyCode: "JPY", Contact: contact, ToDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []StatementLine{{Activity: "Invoice", Number: "INV-010", Amount: decimal.NewFromInt(1235), Balance: decimal.NewFromInt(1235)}}, ClosingBalance: decimal.NewFromInt(1235)}
+	a.Equal("1235", yen.FormatAmount(decimal.RequireFromString("1234.5")))
+	text.Reset()
+	a.NoError(yen.WriteText(text))
+	a.Contains(text.String(), "1235")
+	a.NotContains(text.String(), "1235.00")
+	html.Reset()
+	a.NoError(yen.WriteHTML(html))
+	a.Contains(html.String(), "<td>1235</td>")
+
+	contact, data = testStatementData()
+	statement, err = BuildStatement(contact, data, StatementOptions{Type: StatementOutstanding, ToDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)})
+	a.NoError(err)
+	a.Len(statement.Lines, 2)
+	a.Equal("As at 31 Mar 2024", statement.Period())
+	a.True(decimal.NewFromInt(220).Equal(statement.ClosingBalance))
+
+	//documents in more than one currency do not share a balance
+	contact, data = testStatementData()
+	data.Invoices[0].CurrencyCode = "NZD"
+	data.Invoices[1].CurrencyCode = "USD"
+	data.CreditNotes[0].CurrencyCode = "NZD"
+	_, err = BuildStatement(contact, data, StatementOptions{Type: StatementOutstanding, ToDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)})
+	a.EqualError(err, "contact Ridgeway University has documents in NZD, USD - set the CurrencyCode of the statement")
+	contact.DefaultCurrency = "USD"
+	statement, err = BuildStatement(contact, data, StatementOptions{Type: StatementOutstanding, ToDate: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)})
+	a.NoError(err)
+	a.Equal("USD", statement.CurrencyCode)
+	a.Len(statement.Lines, 1)
+	a.True(decimal.NewFromInt(250).Equal(statement.ClosingBalance))
+}
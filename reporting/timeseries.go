@@ -0,0 +1,309 @@
+package reporting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// maxPeriodsPerReport is the number of columns a single Profit and Loss call returns - the
+// requested period plus 11 comparison periods
+const maxPeriodsPerReport = 12
+
+// Period is a date range covered by a single column of a time series
+type Period struct {
+	FromDate time.Time
+	ToDate   time.Time
+}
+
+// Label returns the period formatted like Xero's column headings e.g. 31 Mar 2024
+func (p Period) Label() string {
+	return p.ToDate.Format("2 Jan 2006")
+}
+
+// TimeSeriesOptions describe the periods of a Profit and Loss time series. Either set ToDate,
+// Periods and Timeframe for consecutive periods, or set Ranges for custom periods
+type TimeSeriesOptions struct {
+	// End of the most recent period
+	ToDate time.Time
+
+	// Number of consecutive periods - more than 12 periods are split across several calls
+	Periods int
+
+	// Length of each period - defaults to a month
+	Timeframe accounting.ReportTimeframe
+
+	// Custom periods - each is run as a separate call
+	Ranges []Period
+
+	// Tracking, layout and paymentsOnly options passed on to every call. Its dates, periods and timeframe are ignored
+	Report accounting.ProfitAndLossOptions
+}
+
+// Series holds the value of a report row for every period of a time series
+type Series struct {
+	// AccountID of the row - empty for total rows such as Net Profit
+	AccountID string
+
+	// Label of the row e.g. the account name
+	Label string
+
+	// Titles of the sections holding the row
+	Path []string
+
+	// Values holds one value per period of the time series, oldest first. Periods the row did not appear in are zero
+	Values []decimal.Decimal
+}
+
+// Change compares the values of a row in two periods
+type Change struct {
+	Period   Period
+	Previous Period
+
+	Value         decimal.Decimal
+	PreviousValue decimal.Decimal
+
+	// Difference is Value - PreviousValue
+	Difference decimal.Decimal
+
+	// Percent is Difference as a percentage of PreviousValue - only set when HasPercent is true
+	Percent    decimal.Decimal
+	HasPercent bool
+}
+
+// TimeSeries is an account by period matrix of Profit and Loss values
+type TimeSeries struct {
+	// Periods oldest first
+	Periods []Period
+
+	// Accounts in the order they first appear on the reports
+	Accounts []*Series
+
+	// Totals are the numeric rows without an AccountID e.g. Total Income and Net Profit
+	Totals []*Series
+}
+
+// Account returns the series of an account
+func (t *TimeSeries) Account(accountID string) *Series {
+	for _, series := range t.Accounts {
+		if series.AccountID == accountID {
+			return series
+		}
+	}
+	return nil
+}
+
+// Total returns the series of a total row by its label
+func (t *TimeSeries) Total(label string) *Series {
+	for _, series := range t.Totals {
+		if series.Label == label {
+			return series
+		}
+	}
+	return nil
+}
+
+// PeriodOverPeriod compares every period of a series with the one before it
+func (t *TimeSeries) PeriodOverPeriod(series *Series) []Change {
+	changes := []Change{}
+	for n := 1; n < len(t.Periods); n++ {
+		changes = append(changes, t.change(series, n, n-1))
+	}
+	return changes
+}
+
+// YearOverYear compares every period of a series with the period ending a year earlier.
+// Periods without a matching period a year earlier are skipped
+func (t *TimeSeries) YearOverYear(series *Series) []Change {
+	changes := []Change{}
+	for n, period := range t.Periods {
+		yearEarlier := period.ToDate.AddDate(-1, 0, 0)
+		for previous, candidate := range t.Periods {
+			if sameMonthEnd(candidate.ToDate, yearEarlier) {
+				changes = append(changes, t.change(series, n, previous))
+				break
+			}
+		}
+	}
+	return changes
+}
+
+// sameMonthEnd reports whether a and b are the same day, treating the last days of the same month as equal
+// so that 29 Feb 2024 matches 28 Feb 2023
+func sameMonthEnd(a time.Time, b time.Time) bool {
+	if a.Equal(b) {
+		return true
+	}
+	return a.Year() == b.Year() && a.Month() == b.Month() && isMonthEnd(a) && isMonthEnd(b)
+}
+
+func isMonthEnd(t time.Time) bool {
+	return t.AddDate(0, 0, 1).Day() == 1
+}
+
+func (t *TimeSeries) change(series *Series, current int, previous int) Change {
+	c := Change{
+		Period:        t.Periods[current],
+		Previous:      t.Periods[previous],
+		Value:         series.Values[current],
+		PreviousValue: series.Values[previous],
+	}
+	c.Difference = c.Value.Sub(c.PreviousValue)
+	if !c.PreviousValue.IsZero() {
+		c.Percent = c.Difference.Div(c.PreviousValue.Abs()).Mul(decimal.NewFromInt(100))
+		c.HasPercent = true
+	}
+	return c
+}
+
+// ConsecutivePeriods returns count periods of the given timeframe ending on toDate, oldest first.
+// The last period ends on toDate and the others cover whole calendar months
+func ConsecutivePeriods(toDate time.Time, count int, timeframe accounting.ReportTimeframe) ([]Period, error) {
+	if timeframe == "" {
+		timeframe = accounting.TimeframeMonth
+	}
+	months := timeframe.Months()
+	if months == 0 {
+		return nil, fmt.Errorf("timeframe must be MONTH, QUARTER or YEAR, not %q", string(timeframe))
+	}
+	if count < 1 {
+		return nil, errors.New("at least one period is needed")
+	}
+
+	toDate = dateOnly(toDate)
+	periods := make([]Period, count)
+	for n := 0; n < count; n++ {
+		//the first day of the month holding the end of this period
+		endMonth := time.Date(toDate.Year(), toDate.Month()-time.Month(n*months), 1, 0, 0, 0, 0, time.UTC)
+		end := endMonth.AddDate(0, 1, -1)
+		if n == 0 {
+			end = toDate
+		}
+		periods[count-1-n] = Period{
+			FromDate: endMonth.AddDate(0, 1-months, 0),
+			ToDate:   end,
+		}
+	}
+	return periods, nil
+}
+
+// RunProfitAndLossSeries runs the Profit and Loss report for every period of options and joins the
+// results into an account by period matrix
+func RunProfitAndLossSeries(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options TimeSeriesOptions) (*TimeSeries, error) {
+	if len(options.Ranges) > 0 {
+		series := NewTimeSeries(options.Ranges)
+		for n, period := range options.Ranges {
+			reportOptions := options.Report
+			reportOptions.FromDate = period.FromDate
+			reportOptions.ToDate = period.ToDate
+			reportOptions.Periods = 0
+			reportOptions.Timeframe = ""
+
+			report, err := runProfitAndLoss(ctx, provider, session, &reportOptions)
+			if err != nil {
+				return nil, err
+			}
+			series.Add(report, []int{n})
+		}
+		return series, nil
+	}
+
+	periods, err := ConsecutivePeriods(options.ToDate, options.Periods, options.Timeframe)
+	if err != nil {
+		return nil, err
+	}
+	series := NewTimeSeries(periods)
+
+	//each call returns its own period followed by the earlier comparison periods, newest first
+	for last := len(periods) - 1; last >= 0; last -= maxPeriodsPerReport {
+		first := last - maxPeriodsPerReport + 1
+		if first < 0 {
+			first = 0
+		}
+
+		reportOptions := options.Report
+		reportOptions.FromDate = periods[last].FromDate
+		reportOptions.ToDate = periods[last].ToDate
+		reportOptions.Periods = last - first
+		reportOptions.Timeframe = options.Timeframe
+
+		report, err := runProfitAndLoss(ctx, provider, session, &reportOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		columns := []int{}
+		for n := last; n >= first; n-- {
+			columns = append(columns, n)
+		}
+		series.Add(report, columns)
+	}
+	return series, nil
+}
+
+func runProfitAndLoss(ctx context.Context, provider xerogolang.IProvider, session goth.Session, options *accounting.ProfitAndLossOptions) (*Report, error) {
+	reports, err := accounting.RunProfitAndLossWithOptions(ctx, provider, session, options)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports.Reports) == 0 {
+		return nil, errors.New("the Profit and Loss report returned no report")
+	}
+	return Parse(reports.Reports[0]), nil
+}
+
+// NewTimeSeries creates an empty time series for the given periods, oldest first
+func NewTimeSeries(periods []Period) *TimeSeries {
+	return &TimeSeries{
+		Periods:  periods,
+		Accounts: []*Series{},
+		Totals:   []*Series{},
+	}
+}
+
+// Add copies the values of a report into the time series. columns holds the index of the period
+// each value column of the report belongs to, in column order
+func (t *TimeSeries) Add(report *Report, columns []int) {
+	for _, row := range report.Rows() {
+		if len(row.Cells) < 2 {
+			continue
+		}
+
+		var series *Series
+		accountID := row.AccountID()
+		if accountID != "" {
+			series = t.Account(accountID)
+		} else {
+			//rows without an account are only kept when they hold numbers
+			if _, ok := ParseAmount(row.Cells[1].Value); !ok {
+				continue
+			}
+			series = t.Total(row.Label())
+		}
+		if series == nil {
+			series = &Series{
+				AccountID: accountID,
+				Label:     row.Label(),
+				Path:      row.Section.Path(),
+				Values:    make([]decimal.Decimal, len(t.Periods)),
+			}
+			if accountID != "" {
+				t.Accounts = append(t.Accounts, series)
+			} else {
+				t.Totals = append(t.Totals, series)
+			}
+		}
+
+		for n, cell := range row.Cells[1:] {
+			if n < len(columns) && cell.IsNumeric {
+				series.Values[columns[n]] = cell.Amount
+			}
+		}
+	}
+}
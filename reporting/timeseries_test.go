@@ -0,0 +1,60 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ConsecutivePeriods(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	periods, err := ConsecutivePeriods(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), 14, accounting.TimeframeMonth)
+	a.NoError(err)
+	a.Len(periods, 14)
+	a.Equal(time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), periods[0].FromDate)
+	a.Equal(time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC), periods[0].ToDate)
+	a.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), periods[12].ToDate)
+	a.Equal("31 Mar 2024", periods[13].Label())
+
+	periods, err = ConsecutivePeriods(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC), 2, accounting.TimeframeQuarter)
+	a.NoError(err)
+	a.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), periods[0].FromDate)
+	a.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), periods[1].FromDate)
+
+	_, err = ConsecutivePeriods(time.Now(), 0, accounting.TimeframeMonth)
+	a.Error(err)
+}
+
+func Test_TimeSeries(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	periods, _ := ConsecutivePeriods(time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), 13, accounting.TimeframeMonth)
+	series := NewTimeSeries(periods)
+
+	report := testProfitAndLoss(t)
+	series.Add(report, []int{12})
+	series.Add(report, []int{0})
+
+	sales := series.Account("5040915e-8ce7-4177-8d08-fde416232f18")
+	a.NotNil(sales)
+	a.Equal([]string{"Income"}, sales.Path)
+	a.True(decimal.NewFromInt(1250).Equal(sales.Values[12]))
+	a.True(sales.Values[6].IsZero())
+	a.NotNil(series.Total("Net Profit"))
+
+	changes := series.PeriodOverPeriod(sales)
+	a.Len(changes, 12)
+	a.True(changes[0].Difference.Equal(decimal.NewFromInt(-1250)))
+	a.True(changes[0].Percent.Equal(decimal.NewFromInt(-100)))
+	a.False(changes[11].HasPercent)
+
+	yearOverYear := series.YearOverYear(sales)
+	a.Len(yearOverYear, 1)
+	a.True(yearOverYear[0].Difference.IsZero())
+}
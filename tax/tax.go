@@ -0,0 +1,319 @@
+// Package tax calculates line item and document totals the way Xero does, so they can be shown
+// before a document is posted and checked against what Xero returns
+package tax
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/markbates/goth"
+	"github.com/omniboost/xerogolang"
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// Line amount types
+const (
+	Exclusive = accounting.LineAmountTypeExclusive
+	Inclusive = accounting.LineAmountTypeInclusive
+	NoTax     = accounting.LineAmountTypeNoTax
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// ComponentAmount is the tax of a single component of a tax rate on a line
+type ComponentAmount struct {
+	Name       string
+	Rate       decimal.Decimal
+	IsCompound bool
+	Amount     decimal.Decimal
+}
+
+// Line holds the calculated amounts of a line item
+type Line struct {
+	// LineAmount as Xero stores it - tax inclusive for Inclusive documents
+	LineAmount decimal.Decimal
+	TaxAmount  decimal.Decimal
+
+	// Discount is the amount taken off Quantity * UnitAmount by the DiscountRate
+	Discount decimal.Decimal
+
+	TaxType    accounting.TaxType
+	Components []ComponentAmount
+}
+
+// Totals holds the calculated amounts of a document
+type Totals struct {
+	// CurrencyCode the amounts are rounded in
+	CurrencyCode    string
+	LineAmountTypes accounting.LineAmountType
+	Lines           []Line
+
+	SubTotal      decimal.Decimal
+	TotalTax      decimal.Decimal
+	Total         decimal.Decimal
+	TotalDiscount decimal.Decimal
+}
+
+// Mismatch is an amount Xero calculated differently
+type Mismatch struct {
+	// Field e.g. Total or LineItems[0].TaxAmount
+	Field string
+
+	Calculated decimal.Decimal
+	Xero       decimal.Decimal
+
+	// CurrencyCode of the document, used to format the amounts
+	CurrencyCode string
+}
+
+func (m Mismatch) String() string {
+	places := xerogolang.MinorUnits(m.CurrencyCode)
+	return fmt.Sprintf("%s: calculated %s, Xero returned %s", m.Field, m.Calculated.StringFixed(places), m.Xero.StringFixed(places))
+}
+
+// Calculator calculates tax using the tax rates of an organisation
+type Calculator struct {
+	// BaseCurrency of the organisation. Amounts of documents without a CurrencyCode and of manual journals are
+	// rounded to its minor units - 2 decimal places when it is empty
+	BaseCurrency string
+
+	rates           map[accounting.TaxType]accounting.TaxRate
+	accountTaxTypes map[string]accounting.TaxType
+}
+
+// NewCalculator creates a calculator from the tax rates of an organisation. Accounts are optional - when given,
+// lines without a TaxType use the TaxType of their account as Xero does
+func NewCalculator(rates []accounting.TaxRate, accounts []accounting.Account) *Calculator {
+	c := &Calculator{
+		rates:           map[accounting.TaxType]accounting.TaxRate{},
+		accountTaxTypes: map[string]accounting.TaxType{},
+	}
+	for _, rate := range rates {
+		c.rates[rate.TaxType] = rate
+	}
+	for _, account := range accounts {
+		if account.Code != "" && account.TaxType != "" {
+			c.accountTaxTypes[account.Code] = account.TaxType
+		}
+	}
+	return c
+}
+
+// FindCalculator creates a calculator from the tax rates, accounts and base currency of the organisation
+func FindCalculator(ctx context.Context, provider xerogolang.IProvider, session goth.Session) (*Calculator, error) {
+	rates, err := accounting.FindTaxRates(ctx, provider, session, nil)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := accounting.FindAccounts(ctx, provider, session, nil)
+	if err != nil {
+		return nil, err
+	}
+	organisations, err := accounting.FindOrganisation(ctx, provider, session)
+	if err != nil {
+		return nil, err
+	}
+	calculator := NewCalculator(rates.TaxRates, accounts.Accounts)
+	if len(organisations.Organisations) > 0 {
+		calculator.BaseCurrency = organisations.Organisations[0].BaseCurrency
+	}
+	return calculator, nil
+}
+
+// places returns the decimal places amounts in a currency are rounded to, using the base currency when it is empty
+func (c *Calculator) places(currencyCode string) (string, int32) {
+	if currencyCode == "" {
+		currencyCode = c.BaseCurrency
+	}
+	return currencyCode, xerogolang.MinorUnits(currencyCode)
+}
+
+// Calculate works out the amounts of line items in a currency. Line amounts are taken from Quantity * UnitAmount
+// less the DiscountRate when both are set, and from LineAmount otherwise. Tax is rounded per line to the minor
+// units of the currency, e.g. whole yen for JPY - an empty currencyCode is the BaseCurrency of the calculator
+func (c *Calculator) Calculate(currencyCode string, lineAmountTypes accounting.LineAmountType, lineItems []accounting.LineItem) (*Totals, error) {
+	if lineAmountTypes == "" {
+		lineAmountTypes = Exclusive
+	}
+	if lineAmountTypes != Exclusive && lineAmountTypes != Inclusive && lineAmountTypes != NoTax {
+		return nil, fmt.Errorf("LineAmountTypes must be Exclusive, Inclusive or NoTax, not %q", lineAmountTypes)
+	}
+
+	currencyCode, places := c.places(currencyCode)
+	totals := &Totals{CurrencyCode: currencyCode, LineAmountTypes: lineAmountTypes, Lines: []Line{}}
+	for n, item := range lineItems {
+		line := Line{LineAmount: item.LineAmount, TaxType: item.TaxType, Components: []ComponentAmount{}}
+		if !item.Quantity.IsZero() && !item.UnitAmount.IsZero() {
+			gross := item.Quantity.Mul(item.UnitAmount)
+			line.LineAmount = gross.Mul(hundred.Sub(item.DiscountRate)).Div(hundred).Round(places)
+			line.Discount = gross.Round(places).Sub(line.LineAmount)
+		}
+		if line.TaxType == "" {
+			line.TaxType = c.accountTaxTypes[item.AccountCode]
+		}
+
+		if lineAmountTypes != NoTax && !line.LineAmount.IsZero() {
+			if line.TaxType == "" {
+				return nil, fmt.Errorf("LineItems[%d] has no TaxType and its account has no default", n)
+			}
+			rate, ok := c.rates[line.TaxType]
+			if !ok {
+				return nil, fmt.Errorf("LineItems[%d] has unknown TaxType %q", n, line.TaxType)
+			}
+			line.TaxAmount, line.Components = tax(rate, line.LineAmount, lineAmountTypes == Inclusive, places)
+		}
+
+		totals.Lines = append(totals.Lines, line)
+		totals.TotalTax = totals.TotalTax.Add(line.TaxAmount)
+		totals.TotalDiscount = totals.TotalDiscount.Add(line.Discount)
+		if lineAmountTypes == Inclusive {
+			totals.SubTotal = totals.SubTotal.Add(line.LineAmount.Sub(line.TaxAmount))
+		} else {
+			totals.SubTotal = totals.SubTotal.Add(line.LineAmount)
+		}
+	}
+	totals.Total = totals.SubTotal.Add(totals.TotalTax)
+	return totals, nil
+}
+
+// JournalLineTax works out the tax of a manual journal line, using the TaxType of its account when the line has none.
+// It can be passed to accounting.ManualJournalBuilder.TaxCalculator
+func (c *Calculator) JournalLineTax(line accounting.ManualJournalLine, inclusive bool) (decimal.Decimal, error) {
+	taxType := line.TaxType
+	if taxType == "" {
+		taxType = c.accountTaxTypes[line.AccountCode]
+	}
+	if taxType == "" || line.LineAmount.IsZero() {
+		return decimal.Zero, nil
+	}
+	rate, ok := c.rates[taxType]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("unknown TaxType %q", taxType)
+	}
+	_, places := c.places("")
+	amount, _ := tax(rate, line.LineAmount, inclusive, places)
+	return amount, nil
+}
+
+// tax works out the tax on an amount, rounded to places. Compound components are charged on the amount plus
+// the tax of the other components. Inclusive amounts already hold the tax
+func tax(rate accounting.TaxRate, amount decimal.Decimal, inclusive bool, places int32) (decimal.Decimal, []ComponentAmount) {
+	//simple and compound hold the tax per unit of net amount
+	simple := decimal.Zero
+	for _, component := range rate.TaxComponents {
+		if !component.IsCompound {
+			simple = simple.Add(component.Rate.Div(hundred))
+		}
+	}
+	compound := decimal.Zero
+	for _, component := range rate.TaxComponents {
+		if component.IsCompound {
+			compound = compound.Add(component.Rate.Div(hundred).Mul(decimal.NewFromInt(1).Add(simple)))
+		}
+	}
+	factor := simple.Add(compound)
+
+	net := amount
+	if inclusive {
+		net = amount.Div(decimal.NewFromInt(1).Add(factor))
+	}
+	components := []ComponentAmount{}
+	for _, component := range rate.TaxComponents {
+		share := component.Rate.Div(hundred)
+		if component.IsCompound {
+			share = share.Mul(decimal.NewFromInt(1).Add(simple))
+		}
+		components = append(components, ComponentAmount{
+			Name:       component.Name,
+			Rate:       component.Rate,
+			IsCompound: component.IsCompound,
+			Amount:     net.Mul(share).Round(places),
+		})
+	}
+	return net.Mul(factor).Round(places), components
+}
+
+// Invoice calculates the amounts of an invoice
+func (c *Calculator) Invoice(invoice *accounting.Invoice) (*Totals, error) {
+	return c.Calculate(invoice.CurrencyCode, invoice.LineAmountTypes, invoice.LineItems)
+}
+
+// CreditNote calculates the amounts of a credit note
+func (c *Calculator) CreditNote(creditNote *accounting.CreditNote) (*Totals, error) {
+	return c.Calculate(creditNote.CurrencyCode, creditNote.LineAmountTypes, creditNote.LineItems)
+}
+
+// BankTransaction calculates the amounts of a bank transaction
+func (c *Calculator) BankTransaction(bankTransaction *accounting.BankTransaction) (*Totals, error) {
+	return c.Calculate(bankTransaction.CurrencyCode, bankTransaction.LineAmountTypes, bankTransaction.LineItems)
+}
+
+// PurchaseOrder calculates the amounts of a purchase order
+func (c *Calculator) PurchaseOrder(purchaseOrder *accounting.PurchaseOrder) (*Totals, error) {
+	return c.Calculate(purchaseOrder.CurrencyCode, purchaseOrder.LineAmountTypes, purchaseOrder.LineItems)
+}
+
+// CheckInvoice compares the amounts Xero returned for an invoice with the calculated amounts
+func (c *Calculator) CheckInvoice(invoice *accounting.Invoice) ([]Mismatch, error) {
+	totals, err := c.Invoice(invoice)
+	if err != nil {
+		return nil, err
+	}
+	return totals.Compare(invoice.LineItems, invoice.SubTotal, invoice.TotalTax, invoice.Total), nil
+}
+
+// CheckCreditNote compares the amounts Xero returned for a credit note with the calculated amounts
+func (c *Calculator) CheckCreditNote(creditNote *accounting.CreditNote) ([]Mismatch, error) {
+	totals, err := c.CreditNote(creditNote)
+	if err != nil {
+		return nil, err
+	}
+	return totals.Compare(creditNote.LineItems, valueOf(creditNote.SubTotal), valueOf(creditNote.TotalTax), valueOf(creditNote.Total)), nil
+}
+
+// CheckBankTransaction compares the amounts Xero returned for a bank transaction with the calculated amounts
+func (c *Calculator) CheckBankTransaction(bankTransaction *accounting.BankTransaction) ([]Mismatch, error) {
+	totals, err := c.BankTransaction(bankTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return totals.Compare(bankTransaction.LineItems, bankTransaction.SubTotal, bankTransaction.TotalTax, bankTransaction.Total), nil
+}
+
+// CheckPurchaseOrder compares the amounts Xero returned for a purchase order with the calculated amounts
+func (c *Calculator) CheckPurchaseOrder(purchaseOrder *accounting.PurchaseOrder) ([]Mismatch, error) {
+	totals, err := c.PurchaseOrder(purchaseOrder)
+	if err != nil {
+		return nil, err
+	}
+	return totals.Compare(purchaseOrder.LineItems, purchaseOrder.SubTotal, purchaseOrder.TotalTax, purchaseOrder.Total), nil
+}
+
+// Compare lists the amounts of a document returned by Xero that differ from the calculated totals
+func (t *Totals) Compare(lineItems []accounting.LineItem, subTotal decimal.Decimal, totalTax decimal.Decimal, total decimal.Decimal) []Mismatch {
+	mismatches := []Mismatch{}
+	compare := func(field string, calculated decimal.Decimal, xero decimal.Decimal) {
+		if !calculated.Equal(xero) {
+			mismatches = append(mismatches, Mismatch{Field: field, Calculated: calculated, Xero: xero, CurrencyCode: t.CurrencyCode})
+		}
+	}
+	for n, line := range t.Lines {
+		if n >= len(lineItems) {
+			break
+		}
+		compare(fmt.Sprintf("LineItems[%d].LineAmount", n), line.LineAmount, lineItems[n].LineAmount)
+		compare(fmt.Sprintf("LineItems[%d].TaxAmount", n), line.TaxAmount, lineItems[n].TaxAmount)
+	}
+	compare("SubTotal", t.SubTotal, subTotal)
+	compare("TotalTax", t.TotalTax, totalTax)
+	compare("Total", t.Total, total)
+	return mismatches
+}
+
+func valueOf(d *decimal.Decimal) decimal.Decimal {
+	if d == nil {
+		return decimal.Zero
+	}
+	return *d
+}
@@ -0,0 +1,124 @@
+package tax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniboost/xerogolang/accounting"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+var testRates = []accounting.TaxRate{
+	{TaxType: "OUTPUT2", TaxComponents: []accounting.TaxComponent{{Name: "GST", Rate: d("15")}}},
+	{TaxType: "COMPOUND", TaxComponents: []accounting.TaxComponent{
+		{Name: "Federal", Rate: d("5")},
+		{Name: "Provincial", Rate: d("9.975"), IsCompound: true},
+	}},
+	{TaxType: "NONE"},
+}
+
+func Test_Calculate(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	calculator := NewCalculator(testRates, []accounting.Account{{Code: "200", TaxType: "OUTPUT2"}})
+
+	totals, err := calculator.Calculate("NZD", Exclusive, []accounting.LineItem{
+		{Quantity: d("3"), UnitAmount: d("19.99"), DiscountRate: d("10"), AccountCode: "200"},
+		{LineAmount: d("100"), TaxType: "COMPOUND"},
+		{LineAmount: d("20"), TaxType: "NONE"},
+	})
+	a.NoError(err)
+	a.Equal("53.97", totals.Lines[0].LineAmount.StringFixed(2))
+	a.Equal("8.10", totals.Lines[0].TaxAmount.StringFixed(2))
+	a.Equal("6.00", totals.Lines[0].Discount.StringFixed(2))
+	a.Equal("15.47", totals.Lines[1].TaxAmount.StringFixed(2))
+	a.Equal("10.47", totals.Lines[1].Components[1].Amount.StringFixed(2))
+	a.True(totals.Lines[2].TaxAmount.IsZero())
+	a.Equal("173.97", totals.SubTotal.StringFixed(2))
+	a.Equal("23.57", totals.TotalTax.StringFixed(2))
+	a.Equal("197.54", totals.Total.StringFixed(2))
+
+	totals, err = calculator.Calculate("NZD", Inclusive, []accounting.LineItem{{LineAmount: d("115"), TaxType: "OUTPUT2"}})
+	a.NoError(err)
+	a.Equal("15.00", totals.TotalTax.StringFixed(2))
+	a.Equal("100.00", totals.SubTotal.StringFixed(2))
+	a.Equal("115.00", totals.Total.StringFixed(2))
+
+	totals, err = calculator.Calculate("NZD", NoTax, []accounting.LineItem{{LineAmount: d("50"), TaxType: "OUTPUT2"}})
+	a.NoError(err)
+	a.True(totals.TotalTax.IsZero())
+
+	//yen are rounded to whole amounts and dinar to three places
+	totals, err = calculator.Calculate("JPY", Exclusive, []accounting.LineItem{{Quantity: d("3"), UnitAmount: d("333.5"), TaxType: "COMPOUND"}})
+	a.NoError(err)
+	a.Equal("1001", totals.Lines[0].LineAmount.String())
+	a.Equal("155", totals.TotalTax.String())
+	a.Equal("JPY", totals.CurrencyCode)
+	calculator.BaseCurrency = "KWD"
+	totals, err = calculator.Calculate("", Exclusive, []accounting.LineItem{{LineAmount: d("10.001"), TaxType: "OUTPUT2"}})
+	a.NoError(err)
+	a.Equal("1.5", totals.TotalTax.String())
+	a.Equal("KWD", totals.CurrencyCode)
+
+	_, err = calculator.Calculate("NZD", Exclusive, []accounting.LineItem{{LineAmount: d("50"), TaxType: "UNKNOWN"}})
+	a.Error(err)
+	_, err = calculator.Calculate("NZD", "Gross", nil)
+	a.Error(err)
+}
+
+func Test_CheckInvoice(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	invoice := &accounting.Invoice{
+		LineAmountTypes: Exclusive,
+		LineItems:       []accounting.LineItem{{LineAmount: d("100"), TaxAmount: d("15"), TaxType: "OUTPUT2"}},
+		SubTotal:        d("100"),
+		TotalTax:        d("15"),
+		Total:           d("115.01"),
+	}
+	mismatches, err := NewCalculator(testRates, nil).CheckInvoice(invoice)
+	a.NoError(err)
+	a.Len(mismatches, 1)
+	a.Equal("Total: calculated 115.00, Xero returned 115.01", mismatches[0].String())
+
+	invoice = &accounting.Invoice{
+		CurrencyCode:    "JPY",
+		LineAmountTypes: Exclusive,
+		LineItems:       []accounting.LineItem{{LineAmount: d("1001"), TaxAmount: d("150"), TaxType: "OUTPUT2"}},
+		SubTotal:        d("1001"),
+		TotalTax:        d("150"),
+		Total:           d("1151"),
+	}
+	mismatches, err = NewCalculator(testRates, nil).CheckInvoice(invoice)
+	a.NoError(err)
+	a.Empty(mismatches)
+	invoice.Total = d("1152")
+	mismatches, err = NewCalculator(testRates, nil).CheckInvoice(invoice)
+	a.NoError(err)
+	a.Equal("Total: calculated 1151, Xero returned 1152", mismatches[0].String())
+}
+
+func Test_JournalLineTax(t *testing.T) {
+	t.Parallel()
+	a := assert.New(t)
+
+	calculator := NewCalculator(testRates, []accounting.Account{{Code: "200", TaxType: "OUTPUT2"}})
+	journals, err := accounting.NewManualJournalBuilder("Sales adjustment", time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)).
+		LineAmountTypes(Exclusive).
+		TaxCalculator(calculator.JournalLineTax).
+		Debit(accounting.ManualJournalLine{AccountCode: "610", TaxType: "NONE"}, d("115")).
+		Credit(accounting.ManualJournalLine{AccountCode: "200"}, d("100")).
+		Build()
+	a.NoError(err)
+	a.Len(journals.ManualJournals[0].JournalLines, 2)
+
+	_, err = calculator.JournalLineTax(accounting.ManualJournalLine{TaxType: "MISSING", LineAmount: d("1")}, false)
+	a.Error(err)
+}
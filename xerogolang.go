@@ -255,9 +255,9 @@ func (p *Provider) processRequest(request *http.Request, session goth.Session, a
 		return nil, fmt.Errorf("%s cannot process request without accessToken", p.providerName)
 	}
 
-	request.Header.Add("User-Agent", p.UserAgentString)
+	request.Header.Set("User-Agent", p.UserAgentString)
 	for key, value := range additionalHeaders {
-		request.Header.Add(key, value)
+		request.Header.Set(key, value)
 	}
 
 	var err error
@@ -283,9 +283,7 @@ func (p *Provider) processRequest(request *http.Request, session goth.Session, a
 	defer response.Body.Close()
 
 	if response.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			helpers.ReaderToString(response.Body),
-		)
+		return nil, fmt.Errorf("%s", helpers.ReaderToString(response.Body))
 	}
 
 	responseBytes, err := io.ReadAll(response.Body)
@@ -327,7 +325,11 @@ func (p *Provider) Create(ctx context.Context, session goth.Session, endpoint st
 		return nil, err
 	}
 
-	return p.processRequest(request, session, additionalHeaders)
+	headers, err := idempotentHeaders(ctx, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return p.processRequest(request, session, headers)
 }
 
 // Update sends data to an endpoint and returns a response to be unmarshaled into the appropriate data type
@@ -339,7 +341,11 @@ func (p *Provider) Update(ctx context.Context, session goth.Session, endpoint st
 		return nil, err
 	}
 
-	return p.processRequest(request, session, additionalHeaders)
+	headers, err := idempotentHeaders(ctx, additionalHeaders)
+	if err != nil {
+		return nil, err
+	}
+	return p.processRequest(request, session, headers)
 }
 
 // Remove deletes the specified data from an endpoint